@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	logs "briefcash-inquiry/internal/helper/loghelper"
 
@@ -10,15 +12,41 @@ import (
 )
 
 type Config struct {
-	DBUrl        string
-	DBHost       string
-	DBUsername   string
-	DBPassword   string
-	DBPort       string
-	DBName       string
-	AppPort      string
-	RedisAddress string
-	RedisPort    string
+	DBUrl                  string
+	DBHost                 string
+	DBUsername             string
+	DBPassword             string
+	DBPort                 string
+	DBName                 string
+	AppPort                string
+	RedisAddress           string
+	RedisPort              string
+	TokenRefreshInterval   time.Duration
+	TokenRefreshLeadTime   time.Duration
+	TokenRefreshMaxRetry   int
+	TokenRefreshRetryDelay time.Duration
+	BreakerFailureLimit    int
+	BreakerOpenTimeout     time.Duration
+	BreakerHalfOpenLimit   int
+	InquiryCacheTTL        time.Duration
+	InquiryNegativeTTL     time.Duration
+	BulkWorkers            int
+	BulkRatePerSecond      int
+	BulkMaxRows            int
+	BulkPollInterval       time.Duration
+	BulkStaleTimeout       time.Duration
+	AsyncWorkers           int
+	AsyncPollInterval      time.Duration
+	AsyncStaleTimeout      time.Duration
+	CallbackTimeout        time.Duration
+	CallbackMaxAttempts    int
+	CallbackBaseDelay      time.Duration
+	CallbackMaxDelay       time.Duration
+	MerchantTokenTTL       time.Duration
+	OpsApiKey              string
+	TimestampWindow        time.Duration
+	RateLimitPerSecond     int
+	RateLimitPerDay        int
 }
 
 func LoadConfig() (*Config, error) {
@@ -41,6 +69,32 @@ func LoadConfig() (*Config, error) {
 			}
 			return ":8080"
 		}(),
+		TokenRefreshInterval:   getEnvDuration("TOKEN_REFRESH_INTERVAL", time.Minute),
+		TokenRefreshLeadTime:   getEnvDuration("TOKEN_REFRESH_LEAD_TIME", 5*time.Minute),
+		TokenRefreshMaxRetry:   getEnvInt("TOKEN_REFRESH_MAX_RETRY", 3),
+		TokenRefreshRetryDelay: getEnvDuration("TOKEN_REFRESH_RETRY_DELAY", 2*time.Second),
+		BreakerFailureLimit:    getEnvInt("CIRCUIT_BREAKER_FAILURE_THRESHOLD", 5),
+		BreakerOpenTimeout:     getEnvDuration("CIRCUIT_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+		BreakerHalfOpenLimit:   getEnvInt("CIRCUIT_BREAKER_HALF_OPEN_REQUESTS", 1),
+		InquiryCacheTTL:        getEnvDuration("INQUIRY_CACHE_TTL", 24*time.Hour),
+		InquiryNegativeTTL:     getEnvDuration("INQUIRY_CACHE_NEGATIVE_TTL", time.Hour),
+		BulkWorkers:            getEnvInt("BULK_INQUIRY_WORKERS", 10),
+		BulkRatePerSecond:      getEnvInt("BULK_INQUIRY_RATE_PER_SECOND", 5),
+		BulkMaxRows:            getEnvInt("BULK_INQUIRY_MAX_ROWS", 10000),
+		BulkPollInterval:       getEnvDuration("BULK_INQUIRY_POLL_INTERVAL", 5*time.Second),
+		BulkStaleTimeout:       getEnvDuration("BULK_INQUIRY_STALE_TIMEOUT", 10*time.Minute),
+		AsyncWorkers:           getEnvInt("ASYNC_INQUIRY_WORKERS", 5),
+		AsyncPollInterval:      getEnvDuration("ASYNC_INQUIRY_POLL_INTERVAL", 2*time.Second),
+		AsyncStaleTimeout:      getEnvDuration("ASYNC_INQUIRY_STALE_TIMEOUT", 5*time.Minute),
+		CallbackTimeout:        getEnvDuration("CALLBACK_TIMEOUT", 10*time.Second),
+		CallbackMaxAttempts:    getEnvInt("CALLBACK_MAX_ATTEMPTS", 6),
+		CallbackBaseDelay:      getEnvDuration("CALLBACK_BASE_DELAY", 30*time.Second),
+		CallbackMaxDelay:       getEnvDuration("CALLBACK_MAX_DELAY", 30*time.Minute),
+		MerchantTokenTTL:       getEnvDuration("MERCHANT_TOKEN_TTL", 15*time.Minute),
+		OpsApiKey:              os.Getenv("OPS_API_KEY"),
+		TimestampWindow:        getEnvDuration("REQUEST_TIMESTAMP_WINDOW", 5*time.Minute),
+		RateLimitPerSecond:     getEnvInt("MERCHANT_RATE_LIMIT_PER_SECOND", 0),
+		RateLimitPerDay:        getEnvInt("MERCHANT_RATE_LIMIT_PER_DAY", 0),
 	}
 
 	if cfg.DBHost == "" {
@@ -48,5 +102,110 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("DB_HOST is not set in environment")
 	}
 
+	if err := cfg.validate(); err != nil {
+		logs.Logger.WithError(err).Error("Invalid configuration")
+		return nil, err
+	}
+
 	return cfg, nil
 }
+
+type durationSetting struct {
+	key   string
+	value time.Duration
+}
+
+type countSetting struct {
+	key   string
+	value int
+}
+
+// validate reject setting that would panic a ticker or stall a worker loop,
+// zero is only allowed where it means disabled or no delay
+func (c *Config) validate() error {
+	positiveDurations := []durationSetting{
+		{"TOKEN_REFRESH_INTERVAL", c.TokenRefreshInterval},
+		{"CIRCUIT_BREAKER_OPEN_TIMEOUT", c.BreakerOpenTimeout},
+		{"INQUIRY_CACHE_TTL", c.InquiryCacheTTL},
+		{"INQUIRY_CACHE_NEGATIVE_TTL", c.InquiryNegativeTTL},
+		{"BULK_INQUIRY_POLL_INTERVAL", c.BulkPollInterval},
+		{"BULK_INQUIRY_STALE_TIMEOUT", c.BulkStaleTimeout},
+		{"ASYNC_INQUIRY_POLL_INTERVAL", c.AsyncPollInterval},
+		{"ASYNC_INQUIRY_STALE_TIMEOUT", c.AsyncStaleTimeout},
+		{"CALLBACK_TIMEOUT", c.CallbackTimeout},
+		{"CALLBACK_BASE_DELAY", c.CallbackBaseDelay},
+		{"CALLBACK_MAX_DELAY", c.CallbackMaxDelay},
+		{"MERCHANT_TOKEN_TTL", c.MerchantTokenTTL},
+		{"REQUEST_TIMESTAMP_WINDOW", c.TimestampWindow},
+	}
+	for _, setting := range positiveDurations {
+		if setting.value <= 0 {
+			return fmt.Errorf("%s must be greater than zero, got %s", setting.key, setting.value)
+		}
+	}
+
+	nonNegativeDurations := []durationSetting{
+		{"TOKEN_REFRESH_LEAD_TIME", c.TokenRefreshLeadTime},
+		{"TOKEN_REFRESH_RETRY_DELAY", c.TokenRefreshRetryDelay},
+	}
+	for _, setting := range nonNegativeDurations {
+		if setting.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %s", setting.key, setting.value)
+		}
+	}
+
+	positiveCounts := []countSetting{
+		{"TOKEN_REFRESH_MAX_RETRY", c.TokenRefreshMaxRetry},
+		{"CIRCUIT_BREAKER_FAILURE_THRESHOLD", c.BreakerFailureLimit},
+		{"CIRCUIT_BREAKER_HALF_OPEN_REQUESTS", c.BreakerHalfOpenLimit},
+		{"BULK_INQUIRY_WORKERS", c.BulkWorkers},
+		{"BULK_INQUIRY_RATE_PER_SECOND", c.BulkRatePerSecond},
+		{"BULK_INQUIRY_MAX_ROWS", c.BulkMaxRows},
+		{"ASYNC_INQUIRY_WORKERS", c.AsyncWorkers},
+		{"CALLBACK_MAX_ATTEMPTS", c.CallbackMaxAttempts},
+	}
+	for _, setting := range positiveCounts {
+		if setting.value <= 0 {
+			return fmt.Errorf("%s must be greater than zero, got %d", setting.key, setting.value)
+		}
+	}
+
+	nonNegativeCounts := []countSetting{
+		{"MERCHANT_RATE_LIMIT_PER_SECOND", c.RateLimitPerSecond},
+		{"MERCHANT_RATE_LIMIT_PER_DAY", c.RateLimitPerDay},
+	}
+	for _, setting := range nonNegativeCounts {
+		if setting.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %d", setting.key, setting.value)
+		}
+	}
+	return nil
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		logs.Logger.WithError(err).Warnf("Invalid duration for %s, using default %s", key, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		logs.Logger.WithError(err).Warnf("Invalid number for %s, using default %d", key, defaultValue)
+		return defaultValue
+	}
+	return number
+}
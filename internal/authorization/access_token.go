@@ -5,6 +5,7 @@ import (
 	"briefcash-inquiry/internal/entity"
 	"briefcash-inquiry/internal/helper/httphelper"
 	"briefcash-inquiry/internal/helper/timehelper"
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
@@ -62,10 +63,26 @@ func signWithRSA(privateKey *rsa.PrivateKey, data string) (string, error) {
 	return base64.StdEncoding.EncodeToString(signature), nil
 }
 
-func GetAccessToken(cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+// TokenRequestOption adjust the SNAP access token request for bank deviating
+// from the default timestamp layout or grant type field name
+type TokenRequestOption struct {
+	TimestampLayout string
+	GrantTypeField  string
+}
+
+var DefaultTokenRequestOption = TokenRequestOption{
+	TimestampLayout: timehelper.ISOLayoutWithMillisAndTimezone,
+	GrantTypeField:  "grant_type",
+}
+
+func GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	return GetAccessTokenWithOption(ctx, client, cfg, DefaultTokenRequestOption, log)
+}
+
+func GetAccessTokenWithOption(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, opt TokenRequestOption, log *logrus.Entry) (dto.SNAPAccessToken, error) {
 	var tokenResponse dto.SNAPAccessToken
 	endpoint := cfg.BaseURL + cfg.AccessTokenURL
-	timestamp := timehelper.FormatTimeToISO7(time.Now())
+	timestamp := timehelper.FormatTimeToISO7Layout(time.Now(), opt.TimestampLayout)
 	stringToSign := fmt.Sprintf("%s|%s", cfg.ClientKey, timestamp)
 
 	log.WithField("step", "read_pem").Info("Reading PEM file")
@@ -88,7 +105,7 @@ func GetAccessToken(cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessTo
 
 	log.WithField("step", "handle_payload").Info("Preparing payload and parse to JSON")
 	payload := map[string]string{
-		"grant_type": "client_credentials",
+		opt.GrantTypeField: "client_credentials",
 	}
 
 	payloadBytes, err := json.Marshal(payload)
@@ -105,8 +122,7 @@ func GetAccessToken(cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessTo
 	}
 
 	log.WithField("step", "send_request").Info("Send request access token to bank")
-	client := httphelper.NewHttpClientHelper(10 * time.Second)
-	resp, httpStatus, err := client.SendRequest("POST", endpoint, payloadBytes, headers)
+	resp, httpStatus, err := client.SendRequest(ctx, "POST", endpoint, payloadBytes, headers)
 
 	log.WithField("step", "handle_error").Info("Checking error return from bank")
 	if err != nil {
@@ -130,3 +146,43 @@ func GetAccessToken(cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessTo
 	log.WithField("step", "finalise_access_token").Info("Access token successfully retrieved from bank")
 	return tokenResponse, nil
 }
+
+func loadPublicKey(pemData []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse PEM block with public key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		key2, err2 := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err2 != nil {
+			return nil, err
+		}
+		return key2, nil
+	}
+
+	pk, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not RSA public key")
+	}
+
+	return pk, nil
+}
+
+// VerifyRSASignature check base64 SHA256withRSA signature of data, the
+// counterpart of signWithRSA used by partner signing request to us
+func VerifyRSASignature(publicKeyPem []byte, data, signature string) error {
+	pk, err := loadPublicKey(publicKeyPem)
+	if err != nil {
+		return err
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	hashed := sha256.Sum256([]byte(data))
+	return rsa.VerifyPKCS1v15(pk, crypto.SHA256, hashed[:], sig)
+}
@@ -0,0 +1,63 @@
+package authorization
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GetOAuthAccessToken request token through plain OAuth2 client credentials
+// grant authenticated with HTTP Basic, used by bank not issuing SNAP token.
+// extraHeaders carry bank specific header such as API key
+func GetOAuthAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, extraHeaders map[string]string, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	var tokenResponse dto.OAuthAccessToken
+	endpoint := cfg.BaseURL + cfg.AccessTokenURL
+
+	log.WithField("step", "handle_payload").Info("Preparing client credentials form payload")
+	payload := url.Values{"grant_type": {"client_credentials"}}.Encode()
+
+	log.WithField("step", "handle_headers").Info("Define header paramaters")
+	credential := base64.StdEncoding.EncodeToString([]byte(cfg.ClientKey + ":" + cfg.ClientSecret))
+	headers := map[string]string{
+		"Content-Type":  "application/x-www-form-urlencoded",
+		"Authorization": "Basic " + credential,
+	}
+	for key, value := range extraHeaders {
+		headers[key] = value
+	}
+
+	log.WithField("step", "send_request").Info("Send request access token to bank")
+	resp, httpStatus, err := client.SendRequest(ctx, "POST", endpoint, []byte(payload), headers)
+	if err != nil {
+		return dto.SNAPAccessToken{}, err
+	}
+
+	if httpStatus != http.StatusOK {
+		return dto.SNAPAccessToken{}, fmt.Errorf("access unauthorized: http code %d", httpStatus)
+	}
+
+	log.WithField("step", "parse_response").Info("Parsing body response from JSON to struct")
+	if err := json.Unmarshal(resp, &tokenResponse); err != nil {
+		return dto.SNAPAccessToken{}, err
+	}
+
+	if tokenResponse.AccessToken == "" {
+		return dto.SNAPAccessToken{}, fmt.Errorf("access token empty, response: %+v", tokenResponse)
+	}
+
+	log.WithField("step", "finalise_access_token").Info("Access token successfully retrieved from bank")
+	return dto.SNAPAccessToken{
+		AccessToken: tokenResponse.AccessToken,
+		TokenType:   tokenResponse.TokenType,
+		ExpiresIn:   int16(min(tokenResponse.ExpiresIn, math.MaxInt16)),
+	}, nil
+}
@@ -3,6 +3,8 @@ package authorization
 import (
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 )
@@ -19,3 +21,39 @@ func HashSignature(httpMethod, relativeUrl, accessToken, bodyHash, timestamp, ap
 	mac.Write([]byte(stringToSign))
 	return hex.EncodeToString(mac.Sum(nil))
 }
+
+// HashCallbackSignature sign outbound callback to merchant, no access token is
+// involved so the string to sign only cover method, path, body and timestamp
+func HashCallbackSignature(httpMethod, relativeUrl, bodyHash, timestamp, secret string) string {
+	stringToSign := fmt.Sprintf("%s:%s:%s:%s", httpMethod, relativeUrl, bodyHash, timestamp)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(stringToSign))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HashSignatureSHA512 is the SNAP symmetric signature variant signed with
+// HMAC-SHA512 and encoded in base64, used by bank following the spec strictly
+func HashSignatureSHA512(httpMethod, relativeUrl, accessToken, bodyHash, timestamp, apiSecret string) string {
+	stringToSign := fmt.Sprintf("%s:%s:%s:%s:%s", httpMethod, relativeUrl, accessToken, bodyHash, timestamp)
+	mac := hmac.New(sha512.New, []byte(apiSecret))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignJWTHS256 wrap claims into a compact JWT signed with HMAC-SHA256, the
+// payload signature scheme of bank host-to-host API outside SNAP
+func SignJWTHS256(claims []byte, secret string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// HashHMACSHA256Base64 sign data with HMAC-SHA256 encoded in base64, used by
+// bank with proprietary string to sign
+func HashHMACSHA256Base64(data, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type asyncInquiryController struct {
+	svc service.AsyncInquiryService
+}
+
+func NewAsyncInquiryController(svc service.AsyncInquiryService) *asyncInquiryController {
+	return &asyncInquiryController{svc}
+}
+
+// InquiryAccountAsync accept the inquiry and return its tracking id right away,
+// the result is delivered later to the merchant callback url
+func (ctr *asyncInquiryController) InquiryAccountAsync(c *gin.Context) {
+	partnerRefNo := c.GetHeader("X-PARTNER-REFERENCE")
+	if partnerRefNo == "" {
+		c.JSON(http.StatusBadRequest, dto.AsyncInquiryResponse{
+			Status:  false,
+			Code:    "CLIENT_MISSING_HEADER",
+			Message: "Missing X-PARTNER-REFERENCE header",
+			Source:  errorhelper.SourceClient,
+		})
+		return
+	}
+
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":  "async_inquiry_controller",
+		"trace_id": partnerRefNo,
+	})
+
+	var req dto.InquiryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.AsyncInquiryResponse{
+			Status:  false,
+			Code:    "CLIENT_ERROR_REQUEST",
+			Message: "Invalid body request",
+			Source:  errorhelper.SourceClient,
+		})
+		return
+	}
+	req.CompanyId = authenticatedMerchant(c)
+
+	log.WithField("step", "submit_inquiry").Info("Submitting async inquiry request")
+	response, err := ctr.svc.Submit(c.Request.Context(), req, partnerRefNo)
+	if err != nil {
+		log.WithField("step", "submit_inquiry").WithError(err).Error("Failed to submit async inquiry")
+		c.JSON(asyncErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+func (ctr *asyncInquiryController) GetAsyncTask(c *gin.Context) {
+	response, err := ctr.svc.GetTask(c.Request.Context(), authenticatedMerchant(c), c.Param("trackingId"))
+	if err != nil {
+		c.JSON(asyncErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func asyncErrorResponse(err error) (int, dto.AsyncInquiryResponse) {
+	switch {
+	case errors.Is(err, service.ErrCallbackNotRegistered):
+		return http.StatusUnprocessableEntity, dto.AsyncInquiryResponse{
+			Status:  false,
+			Code:    "CALLBACK_NOT_REGISTERED",
+			Message: "Callback url is not registered for merchant",
+			Source:  errorhelper.SourceClient,
+		}
+	case errors.Is(err, service.ErrAsyncTaskNotFound):
+		return http.StatusNotFound, dto.AsyncInquiryResponse{
+			Status:  false,
+			Code:    "INQUIRY_NOT_FOUND",
+			Message: "Inquiry not found",
+			Source:  errorhelper.SourceClient,
+		}
+	case errors.Is(err, service.ErrReferenceConflict):
+		return http.StatusConflict, dto.AsyncInquiryResponse{
+			Status:  false,
+			Code:    "CLIENT_REFERENCE_CONFLICT",
+			Message: "X-PARTNER-REFERENCE already used with different payload",
+			Source:  errorhelper.SourceClient,
+		}
+	default:
+		return http.StatusInternalServerError, dto.AsyncInquiryResponse{
+			Status:  false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Internal server error occured",
+			Source:  errorhelper.SourceInternal,
+		}
+	}
+}
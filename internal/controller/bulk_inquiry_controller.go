@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/service"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const bulkMaxBodyBytes = 10 << 20
+
+var bulkResultCsvHeader = []string{
+	"row_no", "partner_reference_no", "beneficary_account", "bank_code", "type",
+	"item_status", "response_code", "response_message", "beneficiary_name",
+}
+
+type bulkInquiryController struct {
+	svc service.BulkInquiryService
+}
+
+func NewBulkInquiryController(svc service.BulkInquiryService) *bulkInquiryController {
+	return &bulkInquiryController{svc}
+}
+
+// CreateBulkJob accept json body or csv upload (Content-Type text/csv),
+// each csv column is named after the json field
+func (ctr *bulkInquiryController) CreateBulkJob(c *gin.Context) {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "bulk_inquiry_controller",
+		"operation": "create_bulk_job",
+	})
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, bulkMaxBodyBytes)
+
+	var req dto.BulkInquiryRequest
+	var err error
+	if c.ContentType() == "text/csv" {
+		req.Items, err = parseBulkCsv(c.Request.Body)
+	} else {
+		err = c.ShouldBindJSON(&req)
+	}
+	if err != nil {
+		log.WithField("step", "payload_validation").WithError(err).Warn("Invalid bulk inquiry payload")
+		c.JSON(bulkErrorResponse(fmt.Errorf("%w: %v", service.ErrInvalidBulkRequest, err)))
+		return
+	}
+
+	log.WithField("step", "create_job").Infof("Creating bulk inquiry job with %d rows", len(req.Items))
+	response, err := ctr.svc.CreateJob(c.Request.Context(), authenticatedMerchant(c), req.Items)
+	if err != nil {
+		log.WithField("step", "create_job").WithError(err).Error("Failed to create bulk inquiry job")
+		c.JSON(bulkErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+func (ctr *bulkInquiryController) GetBulkJob(c *gin.Context) {
+	response, err := ctr.svc.GetJob(c.Request.Context(), authenticatedMerchant(c), c.Param("jobId"))
+	if err != nil {
+		c.JSON(bulkErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (ctr *bulkInquiryController) DownloadBulkResults(c *gin.Context) {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "bulk_inquiry_controller",
+		"operation": "download_bulk_results",
+		"job_id":    c.Param("jobId"),
+	})
+
+	merchantCode := authenticatedMerchant(c)
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(bulkErrorResponse(fmt.Errorf("%w: unknown format %s", service.ErrInvalidBulkRequest, format)))
+		return
+	}
+
+	writer := newExportWriter(c, format, "bulk_inquiry_"+c.Param("jobId"), bulkResultCsvHeader)
+	err := ctr.svc.ExportResults(c.Request.Context(), merchantCode, c.Param("jobId"), func(result dto.BulkInquiryResult) error {
+		return writer.Write([]string{
+			strconv.Itoa(result.RowNo), result.PartnerReferenceNo, result.BeneficiaryAccount, result.BankCode, result.Type,
+			result.ItemStatus, result.ResponseCode, result.ResponseMessage, result.BeneficiaryName,
+		}, result)
+	})
+
+	if err != nil && !writer.Started() {
+		log.WithField("step", "export_results").WithError(err).Error("Failed to export bulk inquiry results")
+		c.JSON(bulkErrorResponse(err))
+		return
+	}
+	if err != nil {
+		log.WithField("step", "export_results").WithError(err).Error("Bulk inquiry results export interrupted")
+	}
+	writer.Close()
+}
+
+// parseBulkCsv read csv with header row, column order is free and unknown
+// column is ignored
+func parseBulkCsv(body io.Reader) ([]dto.BulkInquiryRow, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"beneficary_account", "bank_code"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing csv column %s", required)
+		}
+	}
+
+	value := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []dto.BulkInquiryRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv row: %w", err)
+		}
+
+		rows = append(rows, dto.BulkInquiryRow{
+			BeneficiaryAccount: value(record, "beneficary_account"),
+			PartnerReferenceNo: value(record, "partner_reference_no"),
+			BankCode:           value(record, "bank_code"),
+			Type:               value(record, "type"),
+		})
+	}
+}
+
+func bulkErrorResponse(err error) (int, dto.BulkJobResponse) {
+	switch {
+	case errors.Is(err, service.ErrInvalidBulkRequest):
+		return http.StatusBadRequest, dto.BulkJobResponse{
+			Status:  false,
+			Code:    "CLIENT_ERROR_REQUEST",
+			Message: err.Error(),
+			Source:  errorhelper.SourceClient,
+		}
+	case errors.Is(err, service.ErrBulkJobNotFound):
+		return http.StatusNotFound, dto.BulkJobResponse{
+			Status:  false,
+			Code:    "BULK_JOB_NOT_FOUND",
+			Message: "Bulk inquiry job not found",
+			Source:  errorhelper.SourceClient,
+		}
+	default:
+		return http.StatusInternalServerError, dto.BulkJobResponse{
+			Status:  false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Internal server error occured",
+			Source:  errorhelper.SourceInternal,
+		}
+	}
+}
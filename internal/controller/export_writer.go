@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"briefcash-inquiry/internal/helper/jsonhelper"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportWriter stream rows as csv or json array download, response header is
+// written with the first row so an error before it can still be sent as json
+type exportWriter struct {
+	c        *gin.Context
+	format   string
+	filename string
+	header   []string
+	csv      *csv.Writer
+	started  bool
+}
+
+func newExportWriter(c *gin.Context, format, name string, header []string) *exportWriter {
+	return &exportWriter{
+		c:        c,
+		format:   format,
+		filename: fmt.Sprintf("%s_%s.%s", name, time.Now().Format("20060102150405"), format),
+		header:   header,
+	}
+}
+
+// Write add one row, record is used for csv and value for json
+func (w *exportWriter) Write(record []string, value any) error {
+	if !w.started {
+		w.start()
+	} else if w.format == "json" {
+		if _, err := w.c.Writer.WriteString(","); err != nil {
+			return err
+		}
+	}
+
+	if w.format == "csv" {
+		return w.csv.Write(record)
+	}
+	_, err := w.c.Writer.Write(jsonhelper.WriteToJson(value))
+	return err
+}
+
+func (w *exportWriter) Started() bool {
+	return w.started
+}
+
+func (w *exportWriter) Close() {
+	if !w.started {
+		w.start()
+	}
+
+	if w.format == "csv" {
+		w.csv.Flush()
+		return
+	}
+	_, _ = w.c.Writer.WriteString("]")
+}
+
+func (w *exportWriter) start() {
+	w.started = true
+
+	contentType := "application/json"
+	if w.format == "csv" {
+		contentType = "text/csv"
+	}
+	w.c.Header("Content-Type", contentType)
+	w.c.Header("Content-Disposition", "attachment; filename="+w.filename)
+	w.c.Status(http.StatusOK)
+
+	if w.format == "csv" {
+		w.csv = csv.NewWriter(w.c.Writer)
+		_ = w.csv.Write(w.header)
+		return
+	}
+	_, _ = w.c.Writer.WriteString("[")
+}
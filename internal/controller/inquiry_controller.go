@@ -5,6 +5,8 @@ import (
 	"briefcash-inquiry/internal/helper/errorhelper"
 	"briefcash-inquiry/internal/helper/loghelper"
 	"briefcash-inquiry/internal/service"
+	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -13,11 +15,12 @@ import (
 )
 
 type inquiryController struct {
-	svc service.InquiryService
+	svc         service.InquiryService
+	idempotency service.IdempotencyService
 }
 
-func NewInquiryController(svc service.InquiryService) *inquiryController {
-	return &inquiryController{svc}
+func NewInquiryController(svc service.InquiryService, idempotency service.IdempotencyService) *inquiryController {
+	return &inquiryController{svc, idempotency}
 }
 
 func (ctr *inquiryController) InquiryAccountNumber(c *gin.Context) {
@@ -60,35 +63,77 @@ func (ctr *inquiryController) InquiryAccountNumber(c *gin.Context) {
 		})
 		return
 	}
+	req.CompanyId = authenticatedMerchant(c)
 
 	log.WithField("step", "send_inquiry_request").Info("Sending inquiry account request")
-	response, err := ctr.svc.InquiryAccount(c.Request.Context(), req, partnerRefNo)
+	status, response, err := ctr.idempotency.Execute(c.Request.Context(), req.CompanyId, partnerRefNo, req, func(ctx context.Context) (int, *dto.InquiryResponse) {
+		response, err := ctr.svc.InquiryAccount(ctx, req, partnerRefNo)
 
-	log.WithField("step", "error_validation").Info("Validating error response from bank")
-	if err != nil {
-		log.WithFields(logrus.Fields{
-			"step":            "return_failed_response",
-			"processing_time": time.Since(start).Milliseconds(),
-		}).Error(response.Message)
-
-		statusMap := map[string]int{
-			"INTERNAL_CONNECTION_ERROR": http.StatusGatewayTimeout,
-			"BANK_NO_RESPONSE":          http.StatusGatewayTimeout,
-			"BANK_FORMAT_ERROR":         http.StatusInternalServerError,
-			"INTERNAL_SERVER_ERROR":     http.StatusInternalServerError,
-			"INVALID_BODY":              http.StatusInternalServerError,
-			"UNAUTHORIZED":              http.StatusInternalServerError,
-			"FORBIDDEN_FEATURE":         http.StatusInternalServerError,
-			"ACCOUNT_NOT_FOUND":         http.StatusNotFound,
-			"DUPLICATE_REFERENCE":       http.StatusConflict,
-			"BANK_INTERNAL_ERROR":       http.StatusBadGateway,
-			"BANK_TIMEOUT":              http.StatusGatewayTimeout,
+		log.WithField("step", "error_validation").Info("Validating error response from bank")
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"step":            "return_failed_response",
+				"processing_time": time.Since(start).Milliseconds(),
+			}).Error(response.Message)
+			return errorhelper.InquiryHttpStatus(response.Code), response
 		}
+		return http.StatusOK, response
+	})
 
-		status := statusMap[response.Code]
-		c.JSON(status, response)
+	if err != nil {
+		log.WithField("step", "check_partner_reference").WithError(err).Error("Failed to process partner reference")
+		c.JSON(referenceErrorResponse(err))
+		return
+	}
+
+	c.JSON(status, response)
+}
+
+func (ctr *inquiryController) GetInquiryByReference(c *gin.Context) {
+	partnerRefNo := c.Param("partnerReferenceNo")
+	merchantCode := authenticatedMerchant(c)
+
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":  "inquiry_controller",
+		"trace_id": partnerRefNo,
+	})
+
+	log.WithField("step", "get_inquiry_status").Info("Get inquiry by partner reference")
+	response, err := ctr.svc.GetInquiryStatus(c.Request.Context(), merchantCode, partnerRefNo, c.Query("refresh") == "true")
+	if err != nil {
+		log.WithField("step", "return_failed_response").WithError(err).Error(response.Message)
+		c.JSON(errorhelper.InquiryHttpStatus(response.Code), response)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+func referenceErrorResponse(err error) (int, dto.InquiryResponse) {
+	switch {
+	case errors.Is(err, service.ErrReferenceConflict):
+		return http.StatusConflict, dto.InquiryResponse{
+			Status:  false,
+			Code:    "CLIENT_REFERENCE_CONFLICT",
+			Message: "X-PARTNER-REFERENCE already used with different payload",
+			Source:  errorhelper.SourceClient,
+			Data:    dto.InquiryData{},
+		}
+	case errors.Is(err, service.ErrReferenceInProgress):
+		return http.StatusConflict, dto.InquiryResponse{
+			Status:  false,
+			Code:    "CLIENT_REQUEST_IN_PROGRESS",
+			Message: "Request with the same X-PARTNER-REFERENCE is still in progress",
+			Source:  errorhelper.SourceClient,
+			Data:    dto.InquiryData{},
+		}
+	default:
+		return http.StatusInternalServerError, dto.InquiryResponse{
+			Status:  false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Internal server error occured",
+			Source:  errorhelper.SourceInternal,
+			Data:    dto.InquiryData{},
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var inquiryHistoryCsvHeader = []string{
+	"company_id", "partner_reference_no", "beneficary_account", "bank_code", "beneficiary_name",
+	"partner_bank_code", "type", "inquiry_status", "error_code", "bank_response_code", "latency_ms", "inquiry_date",
+}
+
+type inquiryHistoryController struct {
+	svc service.InquiryHistoryService
+}
+
+func NewInquiryHistoryController(svc service.InquiryHistoryService) *inquiryHistoryController {
+	return &inquiryHistoryController{svc}
+}
+
+// SearchInquiries serve merchant history, merchant only see its own inquiries
+// whatever company_id is sent
+func (ctr *inquiryHistoryController) SearchInquiries(c *gin.Context) {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "inquiry_history_controller",
+		"operation": "search_inquiries",
+	})
+
+	var req dto.InquiryHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(historyErrorResponse(fmt.Errorf("%w: %v", service.ErrInvalidHistoryFilter, err)))
+		return
+	}
+
+	merchantCode := authenticatedMerchant(c)
+	if merchantCode == "" {
+		log.WithField("step", "authenticate").Warn("Inquiry history requested without merchant identity")
+		abortWithClientError(c, http.StatusUnauthorized, errorhelper.ClientMissingAuth)
+		return
+	}
+	req.CompanyId = merchantCode
+
+	ctr.searchInquiries(c, req, log)
+}
+
+// SearchAllInquiries serve ops history, company_id is an optional filter
+func (ctr *inquiryHistoryController) SearchAllInquiries(c *gin.Context) {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "inquiry_history_controller",
+		"operation": "search_all_inquiries",
+	})
+
+	var req dto.InquiryHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(historyErrorResponse(fmt.Errorf("%w: %v", service.ErrInvalidHistoryFilter, err)))
+		return
+	}
+
+	ctr.searchInquiries(c, req, log)
+}
+
+func (ctr *inquiryHistoryController) searchInquiries(c *gin.Context, req dto.InquiryHistoryRequest, log *logrus.Entry) {
+	switch req.Format {
+	case "":
+		log.WithField("step", "search_inquiries").Info("Searching inquiry history")
+		response, err := ctr.svc.SearchInquiries(c.Request.Context(), req)
+		if err != nil {
+			log.WithField("step", "search_inquiries").WithError(err).Error("Failed to search inquiry history")
+			c.JSON(historyErrorResponse(err))
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	case "csv", "json":
+		ctr.exportInquiries(c, req, log)
+	default:
+		c.JSON(historyErrorResponse(fmt.Errorf("%w: unknown format %s", service.ErrInvalidHistoryFilter, req.Format)))
+	}
+}
+
+func (ctr *inquiryHistoryController) exportInquiries(c *gin.Context, req dto.InquiryHistoryRequest, log *logrus.Entry) {
+	writer := newExportWriter(c, req.Format, "inquiry_history", inquiryHistoryCsvHeader)
+
+	log.WithField("step", "export_inquiries").Infof("Exporting inquiry history as %s", req.Format)
+	err := ctr.svc.ExportInquiries(c.Request.Context(), req, func(data dto.InquiryHistoryData) error {
+		return writer.Write([]string{
+			data.MerchantCode, data.PartnerReferenceNo, data.BeneficiaryAccount, data.BankCode, data.BeneficiaryName,
+			data.PartnerBankCode, data.InquiryType, data.InquiryStatus, data.ErrorCode, data.BankResponseCode,
+			strconv.FormatInt(data.LatencyMs, 10), data.InquiryDate,
+		}, data)
+	})
+
+	if err != nil && !writer.Started() {
+		log.WithField("step", "export_inquiries").WithError(err).Error("Failed to export inquiry history")
+		c.JSON(historyErrorResponse(err))
+		return
+	}
+	if err != nil {
+		log.WithField("step", "export_inquiries").WithError(err).Error("Inquiry history export interrupted")
+	}
+	writer.Close()
+}
+
+func historyErrorResponse(err error) (int, dto.InquiryHistoryResponse) {
+	if errors.Is(err, service.ErrInvalidHistoryFilter) {
+		return http.StatusBadRequest, dto.InquiryHistoryResponse{
+			Status:  false,
+			Code:    "CLIENT_INVALID_PARAMETER",
+			Message: err.Error(),
+			Source:  errorhelper.SourceClient,
+			Data:    []dto.InquiryHistoryData{},
+		}
+	}
+	return http.StatusInternalServerError, dto.InquiryHistoryResponse{
+		Status:  false,
+		Code:    "INTERNAL_SERVER_ERROR",
+		Message: "Internal server error occured",
+		Source:  errorhelper.SourceInternal,
+		Data:    []dto.InquiryHistoryData{},
+	}
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/service"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const merchantContextKey = "merchant_code"
+
+type merchantAuthController struct {
+	svc service.MerchantAuthService
+}
+
+func NewMerchantAuthController(svc service.MerchantAuthService) *merchantAuthController {
+	return &merchantAuthController{svc}
+}
+
+// AccessTokenB2B issue merchant access token, request is signed with the
+// merchant private key over X-CLIENT-KEY|X-TIMESTAMP
+func (ctr *merchantAuthController) AccessTokenB2B(c *gin.Context) {
+	clientKey := c.GetHeader("X-CLIENT-KEY")
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":    "merchant_auth_controller",
+		"operation":  "access_token_b2b",
+		"client_key": clientKey,
+	})
+
+	log.WithField("step", "issue_token").Info("Verifying merchant access token request")
+	token, err := ctr.svc.IssueToken(c.Request.Context(), clientKey, c.GetHeader("X-TIMESTAMP"), c.GetHeader("X-SIGNATURE"))
+	if err != nil {
+		log.WithField("step", "issue_token").WithError(err).Warn("Failed to issue merchant access token")
+		status := http.StatusUnauthorized
+		if token.ResponseCode == "" {
+			status = http.StatusInternalServerError
+			token = dto.SNAPAccessToken{ResponseCode: "5007300", ResponseMessage: "Internal Server Error"}
+		}
+		c.JSON(status, token)
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// MerchantAuthMiddleware authenticate merchant by bearer token and symmetric
+// signature, the merchant code is then trusted over any company_id sent by client
+func MerchantAuthMiddleware(svc service.MerchantAuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := loghelper.Logger.WithFields(logrus.Fields{
+			"service": "merchant_auth_middleware",
+			"path":    c.FullPath(),
+		})
+
+		accessToken, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		timestamp := c.GetHeader("X-TIMESTAMP")
+		signature := c.GetHeader("X-SIGNATURE")
+		if !ok || accessToken == "" || timestamp == "" || signature == "" {
+			abortWithClientError(c, http.StatusUnauthorized, errorhelper.ClientMissingAuth)
+			return
+		}
+
+		merchant, err := svc.Authenticate(c.Request.Context(), accessToken)
+		if err != nil {
+			log.WithField("step", "authenticate").WithError(err).Warn("Failed to authenticate merchant")
+			if errors.Is(err, service.ErrInvalidToken) {
+				abortWithClientError(c, http.StatusUnauthorized, errorhelper.ClientInvalidToken)
+				return
+			}
+			abortWithInternalError(c)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, bulkMaxBodyBytes))
+		if err != nil {
+			abortWithClientError(c, http.StatusBadRequest, errorhelper.ClientInvalidSignature)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		err = svc.VerifySignature(merchant, c.Request.Method, c.Request.URL.RequestURI(), accessToken, body, timestamp, signature)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"step":     "verify_signature",
+				"merchant": merchant.MerchantCode,
+			}).WithError(err).Warn("Merchant request signature rejected")
+			if errors.Is(err, service.ErrInvalidTimestamp) {
+				abortWithClientError(c, http.StatusBadRequest, errorhelper.ClientInvalidTimestamp)
+				return
+			}
+			abortWithClientError(c, http.StatusUnauthorized, errorhelper.ClientInvalidSignature)
+			return
+		}
+
+		externalId := c.GetHeader("X-EXTERNAL-ID")
+		if externalId == "" {
+			abortWithClientError(c, http.StatusBadRequest, errorhelper.ClientMissingExternalId)
+			return
+		}
+
+		if err := svc.CheckReplay(c.Request.Context(), merchant.MerchantCode, timestamp, externalId); err != nil {
+			log.WithFields(logrus.Fields{
+				"step":        "check_replay",
+				"merchant":    merchant.MerchantCode,
+				"external_id": externalId,
+			}).WithError(err).Warn("Merchant request rejected by replay protection")
+			switch {
+			case errors.Is(err, service.ErrTimestampExpired):
+				abortWithClientError(c, http.StatusUnauthorized, errorhelper.ClientTimestampExpired)
+			case errors.Is(err, service.ErrDuplicateExtId):
+				abortWithClientError(c, http.StatusConflict, errorhelper.ClientDuplicateExternalId)
+			default:
+				abortWithInternalError(c)
+			}
+			return
+		}
+
+		c.Set(merchantContextKey, merchant.MerchantCode)
+		c.Next()
+	}
+}
+
+// authenticatedMerchant return merchant code set by MerchantAuthMiddleware,
+// empty when the route is not authenticated
+func authenticatedMerchant(c *gin.Context) string {
+	return c.GetString(merchantContextKey)
+}
+
+func abortWithClientError(c *gin.Context, status int, detail errorhelper.ErrorDetail) {
+	c.AbortWithStatusJSON(status, dto.InquiryResponse{
+		Status:  false,
+		Code:    detail.Code,
+		Message: detail.Message,
+		Source:  detail.Source,
+		Data:    dto.InquiryData{},
+	})
+}
+
+func abortWithInternalError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, dto.InquiryResponse{
+		Status:  false,
+		Code:    "INTERNAL_SERVER_ERROR",
+		Message: "Internal server error occured",
+		Source:  errorhelper.SourceInternal,
+		Data:    dto.InquiryData{},
+	})
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"briefcash-inquiry/internal/helper/breakerhelper"
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type opsController struct {
+	breakers *breakerhelper.BreakerRegistry
+}
+
+func NewOpsController(breakers *breakerhelper.BreakerRegistry) *opsController {
+	return &opsController{breakers}
+}
+
+// OpsAuthMiddleware guard ops routes with the X-OPS-API-KEY header,
+// every request is rejected when no key is configured
+func OpsAuthMiddleware(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-OPS-API-KEY")
+		if apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+			loghelper.Logger.WithFields(logrus.Fields{
+				"service": "ops_auth_middleware",
+				"path":    c.FullPath(),
+			}).Warn("Ops request rejected")
+			abortWithClientError(c, http.StatusUnauthorized, errorhelper.ClientInvalidOpsKey)
+			return
+		}
+		c.Next()
+	}
+}
+
+func (ctr *opsController) CircuitBreakerStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   ctr.breakers.Snapshots(),
+	})
+}
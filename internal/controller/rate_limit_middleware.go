@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/service"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimitMiddleware enforce merchant limits, it must run after
+// MerchantAuthMiddleware so the merchant is already known. Redis failure
+// let the request through rather than blocking every merchant
+func RateLimitMiddleware(svc service.RateLimitService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		merchantCode := authenticatedMerchant(c)
+		if merchantCode == "" {
+			c.Next()
+			return
+		}
+
+		bankCode, inquiryType := requestDestination(c)
+		decision, err := svc.Check(c.Request.Context(), merchantCode, bankCode, inquiryType)
+		if err != nil {
+			loghelper.Logger.WithFields(logrus.Fields{
+				"service":  "rate_limit_middleware",
+				"merchant": merchantCode,
+			}).WithError(err).Warn("Failed to check rate limit, allowing request")
+			c.Next()
+			return
+		}
+
+		if decision.Limit > 0 {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.ResetAfter)))
+		}
+
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+			abortWithClientError(c, http.StatusTooManyRequests, errorhelper.ClientRateLimited)
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestDestination peek destination bank code and inquiry type of json body,
+// the body is restored for the handler
+func requestDestination(c *gin.Context) (string, string) {
+	if c.Request.Body == nil || c.Request.Method == http.MethodGet {
+		return "", ""
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return "", ""
+	}
+
+	var payload struct {
+		BankCode string `json:"bank_code"`
+		Type     string `json:"type"`
+	}
+	_ = json.Unmarshal(body, &payload)
+	return payload.BankCode, payload.Type
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,14 @@
+package dto
+
+const (
+	IdempotencyInProgress = "IN_PROGRESS"
+	IdempotencyCompleted  = "COMPLETED"
+)
+
+type IdempotencyRecord struct {
+	State            string           `json:"state"`
+	RequestHash      string           `json:"request_hash"`
+	HttpStatus       int              `json:"http_status,omitempty"`
+	Response         *InquiryResponse `json:"response,omitempty"`
+	BankResponseCode string           `json:"bank_response_code,omitempty"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+type AsyncInquiryResponse struct {
+	Status  bool             `json:"status"`
+	Message string           `json:"message"`
+	Code    string           `json:"code"`
+	Source  string           `json:"source"`
+	Data    AsyncInquiryData `json:"data"`
+}
+
+type AsyncInquiryData struct {
+	TrackingId         string           `json:"tracking_id,omitempty"`
+	PartnerReferenceNo string           `json:"partner_reference_no,omitempty"`
+	TaskStatus         string           `json:"task_status,omitempty"`
+	CallbackAttempts   int              `json:"callback_attempts"`
+	Result             *InquiryResponse `json:"result,omitempty"`
+}
+
+// InquiryCallbackPayload is sent to merchant callback URL, the inquiry
+// response fields are flattened next to the tracking id
+type InquiryCallbackPayload struct {
+	TrackingId         string `json:"tracking_id"`
+	PartnerReferenceNo string `json:"partner_reference_no"`
+	InquiryResponse
+}
@@ -0,0 +1,44 @@
+package dto
+
+type BulkInquiryRequest struct {
+	Items []BulkInquiryRow `json:"items"`
+}
+
+type BulkInquiryRow struct {
+	BeneficiaryAccount string `json:"beneficary_account"`
+	PartnerReferenceNo string `json:"partner_reference_no"`
+	BankCode           string `json:"bank_code"`
+	Type               string `json:"type"` // bifast, online
+}
+
+type BulkJobResponse struct {
+	Status  bool        `json:"status"`
+	Message string      `json:"message"`
+	Code    string      `json:"code"`
+	Source  string      `json:"source"`
+	Data    BulkJobData `json:"data"`
+}
+
+type BulkJobData struct {
+	JobId         string `json:"job_id,omitempty"`
+	CompanyId     string `json:"company_id,omitempty"`
+	JobStatus     string `json:"job_status,omitempty"`
+	TotalRows     int    `json:"total_rows"`
+	ProcessedRows int    `json:"processed_rows"`
+	SuccessRows   int    `json:"success_rows"`
+	FailedRows    int    `json:"failed_rows"`
+	CreatedAt     string `json:"created_at,omitempty"`
+	CompletedAt   string `json:"completed_at,omitempty"`
+}
+
+type BulkInquiryResult struct {
+	RowNo              int    `json:"row_no"`
+	PartnerReferenceNo string `json:"partner_reference_no"`
+	BeneficiaryAccount string `json:"beneficary_account"`
+	BankCode           string `json:"bank_code"`
+	Type               string `json:"type"`
+	ItemStatus         string `json:"item_status"`
+	ResponseCode       string `json:"response_code"`
+	ResponseMessage    string `json:"response_message"`
+	BeneficiaryName    string `json:"beneficiary_name"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// InquiryCacheRecord keep the bank response code beside the response since
+// the public response never serialize it
+type InquiryCacheRecord struct {
+	Response         *InquiryResponse `json:"response"`
+	BankResponseCode string           `json:"bank_response_code,omitempty"`
+	CachedAt         time.Time        `json:"cached_at"`
+}
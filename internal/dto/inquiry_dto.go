@@ -20,6 +20,14 @@ type InquiryData struct {
 	BeneficiaryAccount string `json:"beneficary_account"`
 	BankCode           string `json:"bank_code"`
 	BeneficiaryName    string `json:"beneficiary_name"`
+	PartnerBankCode    string `json:"partner_bank_code,omitempty"`
+	Cached             bool   `json:"cached"`
+	CacheAgeSeconds    int64  `json:"cache_age_seconds,omitempty"`
+	PartnerReferenceNo string `json:"partner_reference_no,omitempty"`
+	InquiryStatus      string `json:"inquiry_status,omitempty"`
+	InquiryDate        string `json:"inquiry_date,omitempty"`
+	BankResponseCode   string `json:"-"`
+	ExternalId         string `json:"-"`
 }
 
 type BCAInternalInquiryRequest struct {
@@ -63,8 +71,8 @@ type CimbExternalInquiryRequest struct {
 }
 
 type PermataInquiryHeaderRequest struct {
-	RequestTimeStamp string `json:"RequestTimeStamp"`
-	CustReffID       string `json:"CustReffID"`
+	RequestTimeStamp string `json:"RequestTimestamp"`
+	CustReffID       string `json:"CustRefID"`
 }
 
 type PermataInternalInquiryBodyRequest struct {
@@ -79,7 +87,7 @@ type PermataInternalInquiryRequest struct {
 type PermataExternalInquiryBodyRequest struct {
 	ToAccount string `json:"ToAccount"`
 	BankId    string `json:"BankId"`
-	BankName  string `json:"BankName"`
+	BankName  string `json:"BankName,omitempty"`
 }
 
 type PermataExternalInquiryRequest struct {
@@ -87,6 +95,29 @@ type PermataExternalInquiryRequest struct {
 	MessageBody   PermataExternalInquiryBodyRequest `json:"XferInfo"`
 }
 
+type PermataInternalInquiryWrapperRequest struct {
+	Request PermataInternalInquiryRequest `json:"AcctInqRq"`
+}
+
+type PermataExternalInquiryWrapperRequest struct {
+	Request PermataExternalInquiryRequest `json:"OlXferInqRq"`
+}
+
+type BNIInternalInquiryRequest struct {
+	ClientId  string `json:"clientId"`
+	AccountNo string `json:"accountNo"`
+	Signature string `json:"signature,omitempty"`
+}
+
+type BNIExternalInquiryRequest struct {
+	ClientId                string `json:"clientId"`
+	CustomerReferenceNumber string `json:"customerReferenceNumber"`
+	AccountNum              string `json:"accountNum"`
+	DestinationBankCode     string `json:"destinationBankCode"`
+	DestinationAccountNum   string `json:"destinationAccountNum"`
+	Signature               string `json:"signature,omitempty"`
+}
+
 type BCAInquiryResponse struct {
 	ResponseCode           string `json:"responseCode"`
 	ResponseMessage        string `json:"responseMessage"`
@@ -151,7 +182,7 @@ type CimbExternalInquiryResponse struct {
 
 type PermataInquiryHeaderResponse struct {
 	ResponseTimestamp string `json:"ResponseTimestamp"`
-	CustReffID        string `json:"CustReffID"`
+	CustReffID        string `json:"CustRefID"`
 	StatusCode        string `json:"StatusCode"`
 	StatusDesc        string `json:"StatusDesc"`
 }
@@ -175,7 +206,154 @@ type PermataExternalInquiryBodyResponse struct {
 
 type PermataExternalInquiryResponse struct {
 	MessageHeader PermataInquiryHeaderResponse       `json:"MsgRsHdr"`
-	MessageBody   PermataExternalInquiryBodyResponse `json:"InqInfo"`
+	MessageBody   PermataExternalInquiryBodyResponse `json:"XferInfo"`
+}
+
+type PermataInternalInquiryWrapperResponse struct {
+	Response *PermataInternalInquiryResponse `json:"AcctInqRs"`
+}
+
+type PermataExternalInquiryWrapperResponse struct {
+	Response *PermataExternalInquiryResponse `json:"OlXferInqRs"`
+}
+
+// SNAPInternalInquiryRequest, SNAPExternalInquiryRequest and SNAPInquiryResponse
+// follow the SNAP standard as is, shared by bank without its own variation
+type SNAPInternalInquiryRequest struct {
+	PartnerReferenceNo   string            `json:"partnerReferenceNo"`
+	BeneficiaryAccountNo string            `json:"beneficiaryAccountNo"`
+	AdditionalInfo       map[string]string `json:"additionalInfo,omitempty"`
+}
+
+type SNAPExternalInquiryRequest struct {
+	PartnerReferenceNo   string            `json:"partnerReferenceNo"`
+	BeneficiaryBankCode  string            `json:"beneficiaryBankCode"`
+	BeneficiaryAccountNo string            `json:"beneficiaryAccountNo"`
+	AdditionalInfo       map[string]string `json:"additionalInfo,omitempty"`
+}
+
+type SNAPInquiryResponse struct {
+	ResponseCode             string            `json:"responseCode"`
+	ResponseMessage          string            `json:"responseMessage"`
+	ReferenceNo              string            `json:"referenceNo"`
+	PartnerReferenceNo       string            `json:"partnerReferenceNo"`
+	BeneficiaryAccountName   string            `json:"beneficiaryAccountName"`
+	BeneficiaryAccountNo     string            `json:"beneficiaryAccountNo"`
+	BeneficiaryAccountStatus string            `json:"beneficiaryAccountStatus"`
+	BeneficiaryAccountType   string            `json:"beneficiaryAccountType"`
+	BeneficiaryBankCode      string            `json:"beneficiaryBankCode"`
+	BeneficiaryBankName      string            `json:"beneficiaryBankName"`
+	Currency                 string            `json:"currency"`
+	AdditionalInfo           map[string]string `json:"additionalInfo"`
+}
+
+type BNIInternalInquiryParameters struct {
+	ResponseCode      string `json:"responseCode"`
+	ResponseMessage   string `json:"responseMessage"`
+	ErrorMessage      string `json:"errorMessage"`
+	ResponseTimestamp string `json:"responseTimestamp"`
+	CustomerName      string `json:"customerName"`
+	AccountCurrency   string `json:"accountCurrency"`
+	AccountNumber     string `json:"accountNumber"`
+	AccountStatus     string `json:"accountStatus"`
+}
+
+type BNIInternalInquiryResult struct {
+	ClientId   string                       `json:"clientId"`
+	Parameters BNIInternalInquiryParameters `json:"parameters"`
+}
+
+type BNIInternalInquiryResponse struct {
+	Result BNIInternalInquiryResult `json:"getInHouseInquiryResponse"`
+}
+
+type BNIExternalInquiryParameters struct {
+	ResponseCode           string `json:"responseCode"`
+	ResponseMessage        string `json:"responseMessage"`
+	ErrorMessage           string `json:"errorMessage"`
+	ResponseTimestamp      string `json:"responseTimestamp"`
+	DestinationAccountNum  string `json:"destinationAccountNum"`
+	DestinationAccountName string `json:"destinationAccountName"`
+	DestinationBankName    string `json:"destinationBankName"`
+	RetrievalReffNum       string `json:"retrievalReffNum"`
+}
+
+type BNIExternalInquiryResult struct {
+	ClientId   string                       `json:"clientId"`
+	Parameters BNIExternalInquiryParameters `json:"parameters"`
+}
+
+type BNIExternalInquiryResponse struct {
+	Result BNIExternalInquiryResult `json:"getInterbankInquiryResponse"`
+}
+
+type BNIErrorParameters struct {
+	ResponseCode    string `json:"responseCode"`
+	ResponseMessage string `json:"responseMessage"`
+	ErrorMessage    string `json:"errorMessage"`
+}
+
+type BNIErrorResult struct {
+	Parameters BNIErrorParameters `json:"parameters"`
+}
+
+type BNIErrorResponse struct {
+	Result BNIErrorResult `json:"Response"`
+}
+
+type AggregatorInquiryRequest struct {
+	BankCode      string `json:"bank_code"`
+	AccountNumber string `json:"account_number"`
+}
+
+type AggregatorStatusRequest struct {
+	ReferenceId string `json:"reference_id"`
+}
+
+type AggregatorValidationData struct {
+	Id                string `json:"id"`
+	ReferenceId       string `json:"reference_id"`
+	BankCode          string `json:"bank_code"`
+	AccountNumber     string `json:"account_number"`
+	AccountHolderName string `json:"account_holder_name"`
+	Status            string `json:"status"`
+	FailureReason     string `json:"failure_reason"`
+}
+
+type AggregatorError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type AggregatorResponse struct {
+	Success bool                      `json:"success"`
+	Data    *AggregatorValidationData `json:"data"`
+	Error   *AggregatorError          `json:"error"`
+}
+
+type SNAPStatusInquiryRequest struct {
+	OriginalPartnerReferenceNo string `json:"originalPartnerReferenceNo"`
+	OriginalExternalId         string `json:"originalExternalId"`
+	ServiceCode                string `json:"serviceCode"`
+	TransactionDate            string `json:"transactionDate"`
+}
+
+type SNAPStatusInquiryResponse struct {
+	ResponseCode               string            `json:"responseCode"`
+	ResponseMessage            string            `json:"responseMessage"`
+	OriginalPartnerReferenceNo string            `json:"originalPartnerReferenceNo"`
+	ServiceCode                string            `json:"serviceCode"`
+	LatestTransactionStatus    string            `json:"latestTransactionStatus"`
+	TransactionStatusDesc      string            `json:"transactionStatusDesc"`
+	BeneficiaryAccountName     string            `json:"beneficiaryAccountName"`
+	AdditionalInfo             map[string]string `json:"additionalInfo"`
+}
+
+type OAuthAccessToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
 }
 
 type SNAPAccessToken struct {
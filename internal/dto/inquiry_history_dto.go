@@ -0,0 +1,38 @@
+package dto
+
+type InquiryHistoryRequest struct {
+	CompanyId          string `form:"company_id"`
+	DateFrom           string `form:"date_from"` // YYYY-MM-DD or RFC3339
+	DateTo             string `form:"date_to"`
+	BankCode           string `form:"bank_code"`
+	Status             string `form:"status"`
+	BeneficiaryAccount string `form:"account"`
+	PartnerReferenceNo string `form:"partner_reference_no"`
+	Cursor             string `form:"cursor"`
+	Limit              int    `form:"limit"`
+	Format             string `form:"format"` // empty for paged json, csv or json for export
+}
+
+type InquiryHistoryResponse struct {
+	Status     bool                 `json:"status"`
+	Message    string               `json:"message"`
+	Code       string               `json:"code"`
+	Source     string               `json:"source"`
+	Data       []InquiryHistoryData `json:"data"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+type InquiryHistoryData struct {
+	MerchantCode       string `json:"company_id"`
+	PartnerReferenceNo string `json:"partner_reference_no"`
+	BeneficiaryAccount string `json:"beneficary_account"`
+	BankCode           string `json:"bank_code"`
+	BeneficiaryName    string `json:"beneficiary_name"`
+	PartnerBankCode    string `json:"partner_bank_code"`
+	InquiryType        string `json:"type"`
+	InquiryStatus      string `json:"inquiry_status"`
+	ErrorCode          string `json:"error_code"`
+	BankResponseCode   string `json:"bank_response_code"`
+	LatencyMs          int64  `json:"latency_ms"`
+	InquiryDate        string `json:"inquiry_date"`
+}
@@ -4,6 +4,8 @@ import "time"
 
 type AccessToken struct {
 	ID          int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	BankCode    string    `gorm:"column:bank_code"`
+	ClientKey   string    `gorm:"column:client_key"`
 	AccessToken string    `gorm:"column:access_token"`
 	TokenType   string    `gorm:"column:token_type"`
 	ExpiresIn   int16     `gorm:"column:expires_in"`
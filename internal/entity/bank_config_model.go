@@ -1,14 +1,63 @@
 package entity
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 type BankConfig struct {
 	BankCode           string `gorm:"column:bank_code"`
 	BankName           string `gorm:"column:bank_name"`
 	ExternalInquiryURL string `gorm:"column:internal_inquiry_url"`
 	InternalInquiryURL string `gorm:"column:external_inquiry_url"`
+	StatusInquiryURL   string `gorm:"column:status_inquiry_url"`
 	AccessTokenURL     string `gorm:"column:access_token_url"`
 	BaseURL            string `gorm:"column:base_url"`
 	ClientKey          string `gorm:"column:client_key"`
 	ClientSecret       string `gorm:"column:client_secret"`
 	PartnerId          string `gorm:"column:partner_id"`
 	ChannelId          string `gorm:"column:channel_id"`
+	HttpTimeoutMs      int    `gorm:"column:http_timeout_ms"`
+	MaxConnsPerHost    int    `gorm:"column:max_conns_per_host"`
+	RetryMaxAttempts   int    `gorm:"column:retry_max_attempts"`
+	RetryBaseDelayMs   int    `gorm:"column:retry_base_delay_ms"`
+	RetryStatusCodes   string `gorm:"column:retry_status_codes"`   // comma separated, e.g. 502,503,504
+	RetryResponseCodes string `gorm:"column:retry_response_codes"` // comma separated SNAP response code
+	IdempotentExtId    bool   `gorm:"column:idempotent_external_id"`
+	BulkRatePerSecond  int    `gorm:"column:bulk_rate_per_second"`
+	SourceAccountNo    string `gorm:"column:source_account_no"` // our account at the partner bank, required by non SNAP interbank inquiry
+	// JSON object merged into SNAP additionalInfo, e.g. {"channel":"H2H"}
+	InternalAdditionalInfo string `gorm:"column:internal_additional_info"`
+	ExternalAdditionalInfo string `gorm:"column:external_additional_info"`
+	SigningKey             string `gorm:"column:signing_key"`       // static key of bank signing request apart from the OAuth secret
+	OrganizationName       string `gorm:"column:organization_name"` // our organization name registered at the bank
+	ProviderType           string `gorm:"column:provider_type"`     // provider serving the partner, empty use the one of BankCode
+	AuthScheme             string `gorm:"column:auth_scheme"`       // basic or api_key, for partner authenticating with static credential
+}
+
+// ProviderKey return the key the partner provider is registered under
+func (c *BankConfig) ProviderKey() string {
+	if c.ProviderType != "" {
+		return c.ProviderType
+	}
+	return c.BankCode
+}
+
+// InquiryAdditionalInfo decode the additionalInfo configured for intrabank or
+// interbank inquiry, an empty setting give an empty map
+func (c *BankConfig) InquiryAdditionalInfo(internal bool) (map[string]string, error) {
+	raw := c.ExternalAdditionalInfo
+	if internal {
+		raw = c.InternalAdditionalInfo
+	}
+
+	info := make(map[string]string)
+	if strings.TrimSpace(raw) == "" {
+		return info, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		return nil, fmt.Errorf("invalid additional info of bank %s: %w", c.BankCode, err)
+	}
+	return info, nil
 }
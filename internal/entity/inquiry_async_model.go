@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+const (
+	AsyncTaskQueued          = "QUEUED"
+	AsyncTaskProcessing      = "PROCESSING"
+	AsyncTaskCallbackPending = "CALLBACK_PENDING"
+	AsyncTaskDelivering      = "DELIVERING"
+	AsyncTaskDelivered       = "DELIVERED"
+	AsyncTaskFailed          = "FAILED"
+)
+
+type InquiryAsyncTask struct {
+	ID                 int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	TrackingId         string    `gorm:"column:tracking_id;uniqueIndex:idx_inquiry_async_task_tracking_id"`
+	MerchantCode       string    `gorm:"column:merchant_code;uniqueIndex:idx_async_task_reference"`
+	PartnerReferenceNo string    `gorm:"column:partner_reference_no;uniqueIndex:idx_async_task_reference"`
+	RequestHash        string    `gorm:"column:request_hash"`
+	RequestBody        string    `gorm:"column:request_body"`
+	ResponseBody       string    `gorm:"column:response_body"`
+	Status             string    `gorm:"column:status;index:idx_async_task_status,priority:1"`
+	CallbackAttempts   int       `gorm:"column:callback_attempts"`
+	NextAttemptAt      time.Time `gorm:"column:next_attempt_at;index:idx_async_task_status,priority:2"`
+	CreatedAt          time.Time `gorm:"column:created_at"`
+	UpdatedAt          time.Time `gorm:"column:updated_at"`
+}
+
+type InquiryCallbackLog struct {
+	ID           int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	TrackingId   string    `gorm:"column:tracking_id;index:idx_inquiry_callback_log_tracking_id"`
+	Attempt      int       `gorm:"column:attempt"`
+	CallbackURL  string    `gorm:"column:callback_url"`
+	HttpStatus   int       `gorm:"column:http_status"`
+	ResponseBody string    `gorm:"column:response_body"`
+	ErrorMessage string    `gorm:"column:error_message"`
+	LatencyMs    int64     `gorm:"column:latency_ms"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+const (
+	BulkJobQueued     = "QUEUED"
+	BulkJobProcessing = "PROCESSING"
+	BulkJobCompleted  = "COMPLETED"
+
+	BulkItemPending = "PENDING"
+	BulkItemSuccess = "SUCCESS"
+	BulkItemFailed  = "FAILED"
+)
+
+type InquiryBulkJob struct {
+	ID            int64      `gorm:"column:id;primaryKey;autoIncrement"`
+	JobId         string     `gorm:"column:job_id;uniqueIndex:idx_inquiry_bulk_job_job_id"`
+	MerchantCode  string     `gorm:"column:merchant_code"`
+	Status        string     `gorm:"column:status;index:idx_bulk_job_status,priority:1"`
+	TotalRows     int        `gorm:"column:total_rows"`
+	ProcessedRows int        `gorm:"column:processed_rows"`
+	SuccessRows   int        `gorm:"column:success_rows"`
+	FailedRows    int        `gorm:"column:failed_rows"`
+	CreatedAt     time.Time  `gorm:"column:created_at;index:idx_bulk_job_status,priority:2"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at"`
+	CompletedAt   *time.Time `gorm:"column:completed_at"`
+}
+
+type InquiryBulkItem struct {
+	ID                 int64      `gorm:"column:id;primaryKey;autoIncrement"`
+	JobId              string     `gorm:"column:job_id;index:idx_bulk_item_job,priority:1"`
+	RowNo              int        `gorm:"column:row_no;index:idx_bulk_item_job,priority:2"`
+	PartnerReferenceNo string     `gorm:"column:partner_reference_no"`
+	BeneficiaryAccount string     `gorm:"column:beneficiary_account"`
+	BankCode           string     `gorm:"column:bank_code"`
+	InquiryType        string     `gorm:"column:inquiry_type"`
+	Status             string     `gorm:"column:status"`
+	ResponseCode       string     `gorm:"column:response_code"`
+	ResponseMessage    string     `gorm:"column:response_message"`
+	BeneficiaryName    string     `gorm:"column:beneficiary_name"`
+	ProcessedAt        *time.Time `gorm:"column:processed_at"`
+}
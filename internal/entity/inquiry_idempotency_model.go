@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type InquiryIdempotency struct {
+	ID                 int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	MerchantCode       string    `gorm:"column:merchant_code;uniqueIndex:idx_idempotency_reference"`
+	PartnerReferenceNo string    `gorm:"column:partner_reference_no;uniqueIndex:idx_idempotency_reference"`
+	RequestHash        string    `gorm:"column:request_hash"`
+	HttpStatus         int       `gorm:"column:http_status"`
+	ResponseBody       string    `gorm:"column:response_body"`
+	BankResponseCode   string    `gorm:"column:bank_response_code"`
+	CreatedAt          time.Time `gorm:"column:created_at"`
+}
@@ -2,13 +2,79 @@ package entity
 
 import "time"
 
+const (
+	InquiryStatusPending   = "PENDING"
+	InquiryStatusSuccess   = "SUCCESS"
+	InquiryStatusNotFound  = "NOT_FOUND"
+	InquiryStatusBankError = "BANK_ERROR"
+	InquiryStatusTimeout   = "TIMEOUT"
+	InquiryStatusUnknown   = "UNKNOWN"
+)
+
+// inquiryStatusStage order the inquiry lifecycle, status may only move to a later stage
+var inquiryStatusStage = map[string]int{
+	InquiryStatusPending:   0,
+	InquiryStatusTimeout:   1,
+	InquiryStatusUnknown:   1,
+	InquiryStatusSuccess:   2,
+	InquiryStatusNotFound:  2,
+	InquiryStatusBankError: 2,
+}
+
 type Inquiry struct {
-	ID                     int64     `gorm:"column:id;primaryKey;autoIncrement"`
-	MerchantCode           string    `gorm:"column:merchant_code"`
-	PartnerReferenceNo     string    `gorm:"column:partner_reference_no"`
-	BeneficiaryAccount     string    `gorm:"column:beneficiary_account"`
+	ID                     int64     `gorm:"column:id;primaryKey;autoIncrement;index:idx_inquiry_merchant_id,priority:2"`
+	MerchantCode           string    `gorm:"column:merchant_code;index:idx_inquiry_merchant_id,priority:1;index:idx_inquiry_merchant_reference,priority:1"`
+	PartnerReferenceNo     string    `gorm:"column:partner_reference_no;index:idx_inquiry_merchant_reference,priority:2"`
+	BeneficiaryAccount     string    `gorm:"column:beneficiary_account;index:idx_inquiry_account"`
 	BeneficiaryBankCode    string    `gorm:"column:beneficiary_bank_code"`
 	BeneficiaryAccountName string    `gorm:"column:beneficiary_account_name"`
-	InquiryDate            time.Time `gorm:"column:inquiry_date"`
-	Status                 string    `gorm:"column:status"`
+	PartnerBankCode        string    `gorm:"column:partner_bank_code"`
+	ExternalId             string    `gorm:"column:external_id"` // X-EXTERNAL-ID of the last request sent to the partner bank
+	InquiryType            string    `gorm:"column:inquiry_type"`
+	InquiryDate            time.Time `gorm:"column:inquiry_date;index:idx_inquiry_status_date,priority:2"`
+	Status                 string    `gorm:"column:status;index:idx_inquiry_status_date,priority:1"`
+	ErrorCode              string    `gorm:"column:error_code"`
+	BankResponseCode       string    `gorm:"column:bank_response_code"`
+	LatencyMs              int64     `gorm:"column:latency_ms"`
+	Cached                 bool      `gorm:"column:cached"` // result served from inquiry cache, partner fields copy the original call
+	UpdatedAt              time.Time `gorm:"column:updated_at"`
+}
+
+// InquiryFilter narrow inquiry history search, empty field is not filtered.
+// CursorID continue the search from the last returned inquiry
+type InquiryFilter struct {
+	MerchantCode       string
+	DateFrom           time.Time
+	DateTo             time.Time
+	BankCode           string
+	Status             string
+	BeneficiaryAccount string
+	PartnerReferenceNo string
+	CursorID           int64
+	Limit              int
+}
+
+func IsValidInquiryStatus(status string) bool {
+	_, ok := inquiryStatusStage[status]
+	return ok
+}
+
+// IsInconclusive tell whether the bank result of the inquiry is still unknown
+func (i *Inquiry) IsInconclusive() bool {
+	switch i.Status {
+	case InquiryStatusPending, InquiryStatusTimeout, InquiryStatusUnknown:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo validate the next status, a final result can not be changed
+// and an inconclusive result can only be resolved to a final one
+func (i *Inquiry) CanTransitionTo(status string) bool {
+	current, ok := inquiryStatusStage[i.Status]
+	if !ok {
+		return false
+	}
+	next, ok := inquiryStatusStage[status]
+	return ok && next > current
 }
@@ -0,0 +1,13 @@
+package entity
+
+// InquiryRoute map destination bank, inquiry type and merchant to the partner
+// bank whose API is used. Empty merchant or type and "*" destination match any value.
+// Several rows with the same key form the failover list, ordered by priority ascending.
+type InquiryRoute struct {
+	ID                  int64  `gorm:"column:id;primaryKey;autoIncrement"`
+	MerchantCode        string `gorm:"column:merchant_code"`
+	DestinationBankCode string `gorm:"column:destination_bank_code"`
+	InquiryType         string `gorm:"column:inquiry_type"`
+	PartnerBankCode     string `gorm:"column:partner_bank_code"`
+	Priority            int    `gorm:"column:priority"`
+}
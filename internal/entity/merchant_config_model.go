@@ -0,0 +1,11 @@
+package entity
+
+type MerchantConfig struct {
+	MerchantCode        string `gorm:"column:merchant_code"`
+	DisableInquiryCache bool   `gorm:"column:disable_inquiry_cache"`
+	CallbackURL         string `gorm:"column:callback_url"`
+	CallbackSecret      string `gorm:"column:callback_secret"`
+	ClientKey           string `gorm:"column:client_key"`
+	ClientSecret        string `gorm:"column:client_secret"`
+	PublicKey           string `gorm:"column:public_key"` // PEM encoded RSA public key
+}
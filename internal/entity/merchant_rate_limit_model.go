@@ -0,0 +1,11 @@
+package entity
+
+// MerchantRateLimit configure request limit of a merchant, empty BankCode apply
+// to every request of the merchant, otherwise BankCode is the partner bank
+// receiving the traffic. Zero value means no limit
+type MerchantRateLimit struct {
+	MerchantCode string `gorm:"column:merchant_code"`
+	BankCode     string `gorm:"column:bank_code"`
+	PerSecond    int    `gorm:"column:per_second"`
+	PerDay       int    `gorm:"column:per_day"`
+}
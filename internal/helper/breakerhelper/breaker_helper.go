@@ -0,0 +1,185 @@
+package breakerhelper
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+type State string
+
+const (
+	StateClosed   State = "CLOSED"
+	StateOpen     State = "OPEN"
+	StateHalfOpen State = "HALF_OPEN"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type BreakerConfig struct {
+	FailureThreshold    int
+	OpenTimeout         time.Duration
+	HalfOpenMaxRequests int
+}
+
+type BreakerSnapshot struct {
+	Name                string     `json:"name"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+}
+
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	name             string
+	cfg              BreakerConfig
+	state            State
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+	halfOpenSuccess  int
+}
+
+func NewCircuitBreaker(name string, cfg BreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenMaxRequests <= 0 {
+		cfg.HalfOpenMaxRequests = 1
+	}
+	return &CircuitBreaker{name: name, cfg: cfg, state: StateClosed}
+}
+
+// Allow check whether a request may be sent, an open circuit move to half-open
+// after the open timeout and only let a limited number of trial requests through
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		b.halfOpenInFlight = 0
+		b.halfOpenSuccess = 0
+		fallthrough
+	case StateHalfOpen:
+		if b.halfOpenInFlight >= b.cfg.HalfOpenMaxRequests {
+			return ErrCircuitOpen
+		}
+		b.halfOpenInFlight++
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateHalfOpen:
+		b.halfOpenInFlight--
+		b.halfOpenSuccess++
+		if b.halfOpenSuccess >= b.cfg.HalfOpenMaxRequests {
+			b.state = StateClosed
+			b.failures = 0
+		}
+	case StateClosed:
+		b.failures = 0
+	}
+}
+
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateHalfOpen:
+		b.open()
+	case StateClosed:
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	}
+}
+
+// Abort release the trial slot of a request that ended without a bank verdict,
+// such as a request cancelled by the caller
+func (b *CircuitBreaker) Abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+}
+
+func (b *CircuitBreaker) Snapshot() BreakerSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	snapshot := BreakerSnapshot{
+		Name:                b.name,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+	}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		snapshot.OpenedAt = &openedAt
+	}
+	return snapshot
+}
+
+func (b *CircuitBreaker) open() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.halfOpenInFlight = 0
+	b.halfOpenSuccess = 0
+}
+
+type BreakerRegistry struct {
+	mu       sync.Mutex
+	cfg      BreakerConfig
+	breakers map[string]*CircuitBreaker
+}
+
+func NewBreakerRegistry(cfg BreakerConfig) *BreakerRegistry {
+	return &BreakerRegistry{cfg: cfg, breakers: make(map[string]*CircuitBreaker)}
+}
+
+func (r *BreakerRegistry) Get(name string) *CircuitBreaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	breaker, ok := r.breakers[name]
+	if !ok {
+		breaker = NewCircuitBreaker(name, r.cfg)
+		r.breakers[name] = breaker
+	}
+	return breaker
+}
+
+func (r *BreakerRegistry) Snapshots() []BreakerSnapshot {
+	r.mu.Lock()
+	breakers := make([]*CircuitBreaker, 0, len(r.breakers))
+	for _, breaker := range r.breakers {
+		breakers = append(breakers, breaker)
+	}
+	r.mu.Unlock()
+
+	snapshots := make([]BreakerSnapshot, 0, len(breakers))
+	for _, breaker := range breakers {
+		snapshots = append(snapshots, breaker.Snapshot())
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots
+}
@@ -3,6 +3,7 @@ package errorhelper
 import (
 	"briefcash-inquiry/internal/dto"
 	"fmt"
+	"net/http"
 )
 
 type ErrorDetail struct {
@@ -19,12 +20,15 @@ const (
 )
 
 var ErrorMap = map[int]ErrorDetail{
+	202: {Code: "BANK_PENDING", Message: "Inquiry is still processed by bank, please use check status service", LogMessage: "Bank accepted inquiry without final result", Source: SourceBank},
 	400: {Code: "INVALID_BODY", Message: "Invalid payload request", LogMessage: "Invalid body verified by bank", Source: SourceBank},
 	401: {Code: "UNAUTHORIZED", Message: "Access unauthorized", LogMessage: "Bank return unauthorized access", Source: SourceBank},
 	403: {Code: "FORBIDDEN_FEATURE", Message: "Service not allowed", LogMessage: "Feature forbidden by bank", Source: SourceBank},
 	404: {Code: "ACCOUNT_NOT_FOUND", Message: "Account number not found", LogMessage: "Account number not found in bank system", Source: SourceBank},
 	409: {Code: "DUPLICATE_REFERENCE", Message: "Duplicate external id in same day", LogMessage: "Duplicate external id request", Source: SourceBank},
 	500: {Code: "BANK_INTERNAL_ERROR", Message: "Bank internal error, please use check status service", LogMessage: "Bank returned internal error", Source: SourceBank},
+	502: {Code: "BANK_BAD_GATEWAY", Message: "Bank gateway error, please use check status service", LogMessage: "Bank returned bad gateway", Source: SourceBank},
+	503: {Code: "BANK_UNAVAILABLE", Message: "Bank service unavailable", LogMessage: "Bank service unavailable", Source: SourceBank},
 	504: {Code: "BANK_TIMEOUT", Message: "Bank timeout, please use check status service", LogMessage: "Bank timeout while processing request", Source: SourceBank},
 }
 
@@ -35,6 +39,64 @@ var DefaultBankError = ErrorDetail{
 	Source:     SourceBank,
 }
 
+// client errors raised while authenticating and limiting inbound merchant request
+var (
+	ClientMissingAuth         = ErrorDetail{Code: "CLIENT_MISSING_AUTH", Message: "Missing authorization header", LogMessage: "Request without authorization header", Source: SourceClient}
+	ClientInvalidToken        = ErrorDetail{Code: "CLIENT_INVALID_TOKEN", Message: "Access token invalid", LogMessage: "Unknown or expired merchant access token", Source: SourceClient}
+	ClientInvalidTimestamp    = ErrorDetail{Code: "CLIENT_INVALID_TIMESTAMP", Message: "Invalid X-TIMESTAMP format", LogMessage: "Request timestamp can not be parsed", Source: SourceClient}
+	ClientInvalidSignature    = ErrorDetail{Code: "CLIENT_INVALID_SIGNATURE", Message: "Invalid signature", LogMessage: "Request signature does not match", Source: SourceClient}
+	ClientTimestampExpired    = ErrorDetail{Code: "CLIENT_TIMESTAMP_EXPIRED", Message: "X-TIMESTAMP is outside the allowed window", LogMessage: "Request timestamp too old or in the future", Source: SourceClient}
+	ClientMissingExternalId   = ErrorDetail{Code: "CLIENT_MISSING_EXTERNAL_ID", Message: "Missing X-EXTERNAL-ID header", LogMessage: "Request without external id", Source: SourceClient}
+	ClientDuplicateExternalId = ErrorDetail{Code: "CLIENT_DUPLICATE_EXTERNAL_ID", Message: "X-EXTERNAL-ID already used today", LogMessage: "Replayed or duplicate external id", Source: SourceClient}
+	ClientInvalidOpsKey       = ErrorDetail{Code: "CLIENT_INVALID_OPS_KEY", Message: "Invalid or missing ops api key", LogMessage: "Ops request without valid api key", Source: SourceClient}
+	ClientRateLimited         = ErrorDetail{Code: "CLIENT_RATE_LIMITED", Message: "Too many requests, please retry later", LogMessage: "Merchant rate limit exceeded", Source: SourceClient}
+)
+
+// retrySafeCodes are failures where the bank did not produce an inquiry result,
+// so the same inquiry can be sent again or through another partner bank
+var retrySafeCodes = map[string]bool{
+	"INTERNAL_CONNECTION_ERROR": true,
+	"BANK_NO_RESPONSE":          true,
+	"BANK_NOT_SUPPORTED":        true,
+	"BANK_CIRCUIT_OPEN":         true,
+	"BANK_INTERNAL_ERROR":       true,
+	"BANK_BAD_GATEWAY":          true,
+	"BANK_UNAVAILABLE":          true,
+	"BANK_TIMEOUT":              true,
+}
+
+func IsRetrySafe(code string) bool {
+	return retrySafeCodes[code]
+}
+
+// inquiryStatusMap translate inquiry response code into the HTTP status returned to merchant
+var inquiryStatusMap = map[string]int{
+	"INTERNAL_CONNECTION_ERROR": http.StatusGatewayTimeout,
+	"BANK_NO_RESPONSE":          http.StatusGatewayTimeout,
+	"BANK_FORMAT_ERROR":         http.StatusInternalServerError,
+	"INTERNAL_SERVER_ERROR":     http.StatusInternalServerError,
+	"INVALID_BODY":              http.StatusInternalServerError,
+	"UNAUTHORIZED":              http.StatusInternalServerError,
+	"FORBIDDEN_FEATURE":         http.StatusInternalServerError,
+	"ACCOUNT_NOT_FOUND":         http.StatusNotFound,
+	"DUPLICATE_REFERENCE":       http.StatusConflict,
+	"BANK_INTERNAL_ERROR":       http.StatusBadGateway,
+	"BANK_BAD_GATEWAY":          http.StatusBadGateway,
+	"BANK_UNAVAILABLE":          http.StatusServiceUnavailable,
+	"BANK_CIRCUIT_OPEN":         http.StatusServiceUnavailable,
+	"BANK_TIMEOUT":              http.StatusGatewayTimeout,
+	"BANK_NOT_SUPPORTED":        http.StatusUnprocessableEntity,
+	"ROUTE_NOT_FOUND":           http.StatusUnprocessableEntity,
+	"INQUIRY_NOT_FOUND":         http.StatusNotFound,
+}
+
+func InquiryHttpStatus(code string) int {
+	if status, ok := inquiryStatusMap[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
 func BuildErrorResponse(errDetail ErrorDetail, message string, err error) (*dto.InquiryResponse, error) {
 	return &dto.InquiryResponse{
 		Status:  false,
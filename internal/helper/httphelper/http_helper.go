@@ -2,30 +2,67 @@ package httphelper
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
+type ClientConfig struct {
+	Timeout             time.Duration
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+}
+
+var DefaultClientConfig = ClientConfig{
+	Timeout:             10 * time.Second,
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: 20,
+	MaxConnsPerHost:     50,
+	IdleConnTimeout:     90 * time.Second,
+}
+
 type HttpClientHelper struct {
 	client *http.Client
 }
 
-func NewHttpClientHelper(timeout time.Duration) *HttpClientHelper {
+// NewHttpClientHelper create long-lived client, the transport keep connection
+// alive and is meant to be shared by every request to the same bank
+func NewHttpClientHelper(cfg ClientConfig) *HttpClientHelper {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
 	return &HttpClientHelper{client: &http.Client{
-		Timeout: timeout,
+		Timeout:   cfg.Timeout,
+		Transport: transport,
 	}}
 }
 
-func (c *HttpClientHelper) SendRequest(method, url string, payload []byte, headers map[string]string) ([]byte, int, error) {
+func (c *HttpClientHelper) SendRequest(ctx context.Context, method, url string, payload []byte, headers map[string]string) ([]byte, int, error) {
 	var body io.Reader
 
 	if payload != nil {
 		body = bytes.NewBuffer(payload)
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to create request: %v", err)
 	}
@@ -36,13 +73,36 @@ func (c *HttpClientHelper) SendRequest(method, url string, payload []byte, heade
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to send request: %v", err)
+		return nil, 0, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, resp.StatusCode, fmt.Errorf("failed to read response: %v", err)
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
 	}
 	return respBody, resp.StatusCode, nil
 }
+
+// ClientRegistry keep one client per bank, so each bank has its own
+// connection pool and timeout
+type ClientRegistry struct {
+	mu      sync.Mutex
+	clients map[string]*HttpClientHelper
+}
+
+func NewClientRegistry() *ClientRegistry {
+	return &ClientRegistry{clients: make(map[string]*HttpClientHelper)}
+}
+
+func (r *ClientRegistry) Get(name string, cfg ClientConfig) *HttpClientHelper {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	client, ok := r.clients[name]
+	if !ok {
+		client = NewHttpClientHelper(cfg)
+		r.clients[name] = client
+	}
+	return client
+}
@@ -0,0 +1,71 @@
+package ratehelper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter space out requests evenly so no more than the configured rate
+// per second is sent
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func NewLimiter(ratePerSecond int) *Limiter {
+	if ratePerSecond <= 0 {
+		ratePerSecond = 1
+	}
+	return &Limiter{interval: time.Second / time.Duration(ratePerSecond)}
+}
+
+// Wait block until the caller may send its request or ctx is done
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// LimiterRegistry keep one limiter per name, the rate is taken when the
+// limiter is first created
+type LimiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[string]*Limiter
+}
+
+func NewLimiterRegistry() *LimiterRegistry {
+	return &LimiterRegistry{limiters: make(map[string]*Limiter)}
+}
+
+func (r *LimiterRegistry) Get(name string, ratePerSecond int) *Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limiter, ok := r.limiters[name]
+	if !ok {
+		limiter = NewLimiter(ratePerSecond)
+		r.limiters[name] = limiter
+	}
+	return limiter
+}
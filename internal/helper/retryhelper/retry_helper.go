@@ -0,0 +1,67 @@
+package retryhelper
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxAttempts            int
+	BaseDelay              time.Duration
+	MaxDelay               time.Duration
+	Jitter                 float64
+	RetryableStatus        map[int]bool
+	RetryableResponseCodes map[string]bool
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	Jitter:      0.5,
+	RetryableStatus: map[int]bool{
+		502: true,
+		503: true,
+		504: true,
+	},
+	RetryableResponseCodes: map[string]bool{},
+}
+
+func (p RetryPolicy) IsRetryable(httpStatus int, responseCode string) bool {
+	return p.RetryableStatus[httpStatus] || (responseCode != "" && p.RetryableResponseCodes[responseCode])
+}
+
+// Backoff return exponential delay of the given attempt (start from 1),
+// randomized by jitter fraction so parallel retries do not hit the bank together
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		spread := float64(delay) * p.Jitter
+		delay = time.Duration(float64(delay) - spread + rand.Float64()*2*spread)
+	}
+	return delay
+}
+
+// Wait sleep for the delay unless the context is done first, or the delay
+// would already pass the context deadline
+func Wait(ctx context.Context, delay time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+		return fmt.Errorf("retry delay %s exceed request deadline", delay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
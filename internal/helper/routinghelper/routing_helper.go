@@ -3,45 +3,110 @@ package routinghelper
 import (
 	"briefcash-inquiry/internal/dto"
 	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
 	"briefcash-inquiry/internal/mapper"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
 )
 
-type BankRouteRequest interface {
-	BuildBodyRequest() []byte
-	GetUrl() string
-	GetHeaders(accessToken, externalId string, cfg *entity.BankConfig, payload []byte) map[string]string
-}
-
-func NewBankRouteRequest(req dto.InquiryRequest, cfg *entity.BankConfig, partnerRefNo string) BankRouteRequest {
-	switch req.BankCode {
-	case "002":
-		return mapper.NewBriClientRequest(cfg, req)
-	case "013":
-		return mapper.NewPermataClientRequest(cfg, req)
-	case "014":
-		return mapper.NewBcaClientRequest(cfg, req)
-	case "022":
-		return mapper.NewCimbClientRequest(cfg, req)
-	default:
-		return mapper.NewBcaClientRequest(cfg, req)
+// Provider combine everything needed to call inquiry API of a partner bank.
+// cfg always belong to the partner bank executing the inquiry, while
+// req.BankCode is the destination bank of the beneficiary account
+type Provider interface {
+	Code() string
+	Name() string
+	BuildBodyRequest(cfg *entity.BankConfig, req dto.InquiryRequest) []byte
+	GetUrl(cfg *entity.BankConfig, req dto.InquiryRequest) string
+	GetHeaders(cfg *entity.BankConfig, req dto.InquiryRequest, accessToken, externalId string, payload []byte) map[string]string
+	MapResponse(cfg *entity.BankConfig, req dto.InquiryRequest, httpStatus int, bankResponse []byte) (mapper.BankResponseData, error)
+	GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error)
+}
+
+// StatusProvider is implemented by provider able to re-query the result of a
+// previous inquiry through the bank transaction status API
+type StatusProvider interface {
+	BuildStatusRequest(cfg *entity.BankConfig, inquiry *entity.Inquiry) []byte
+	GetStatusUrl(cfg *entity.BankConfig) string
+	GetStatusHeaders(cfg *entity.BankConfig, accessToken, externalId string, payload []byte) map[string]string
+	MapStatusResponse(cfg *entity.BankConfig, httpStatus int, bankResponse []byte) (mapper.BankResponseData, error)
+}
+
+// NumericExternalIdProvider is implemented by provider whose bank only accept
+// digits in X-EXTERNAL-ID
+type NumericExternalIdProvider interface {
+	RequiresNumericExternalId() bool
+}
+
+// RequiresNumericExternalId tell whether the provider need a numeric external id
+func RequiresNumericExternalId(provider Provider) bool {
+	numeric, ok := provider.(NumericExternalIdProvider)
+	return ok && numeric.RequiresNumericExternalId()
+}
+
+// StaticAuthProvider is implemented by provider authenticating every request
+// with its configured credential, no access token is requested nor cached
+type StaticAuthProvider interface {
+	UsesStaticAuth() bool
+}
+
+// UsesStaticAuth tell whether the provider skip access token handling
+func UsesStaticAuth(provider Provider) bool {
+	staticAuth, ok := provider.(StaticAuthProvider)
+	return ok && staticAuth.UsesStaticAuth()
+}
+
+type ProviderRegistry interface {
+	Register(provider Provider)
+	Resolve(key string) (Provider, error)
+}
+
+type providerRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+func NewProviderRegistry(providers ...Provider) ProviderRegistry {
+	registry := &providerRegistry{providers: make(map[string]Provider)}
+	for _, provider := range providers {
+		registry.Register(provider)
 	}
+	return registry
 }
 
-type BankRouteResponse interface {
-	MapResponse(bankResponse []byte) mapper.BankResponseData
+// NewDefaultProviderRegistry register all partner bank supported by this service
+func NewDefaultProviderRegistry() ProviderRegistry {
+	return NewProviderRegistry(
+		mapper.NewBriProvider(),
+		mapper.NewPermataProvider(),
+		mapper.NewBcaProvider(),
+		mapper.NewCimbProvider(),
+		mapper.NewMandiriProvider(),
+		mapper.NewBniProvider(),
+		mapper.NewBsiProvider(),
+		mapper.NewBtnProvider(),
+		mapper.NewAggregatorProvider(),
+	)
 }
 
-func NewBankRouteResponse(cfg *entity.BankConfig, httpStatus int) BankRouteResponse {
-	switch cfg.BankCode {
-	case "002":
-		return mapper.NewBriClientResponse(cfg, httpStatus)
-	case "013":
-		return mapper.NewPermataClientResponse(cfg)
-	case "014":
-		return mapper.NewBcaClientResponse(cfg)
-	case "022":
-		return mapper.NewCimbClientResponse(cfg)
-	default:
-		return mapper.NewBcaClientResponse(cfg)
+func (r *providerRegistry) Register(provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.providers[provider.Code()] = provider
+	r.providers[strings.ToUpper(provider.Name())] = provider
+}
+
+func (r *providerRegistry) Resolve(key string) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	provider, ok := r.providers[strings.ToUpper(key)]
+	if !ok {
+		return nil, fmt.Errorf("no provider registered for bank %q", key)
 	}
+	return provider, nil
 }
@@ -2,13 +2,23 @@ package timehelper
 
 import "time"
 
-const ISOLayoutWithMillisAndTimezone = "2006-01-02T15:04:05.000-07.00"
+const (
+	ISOLayoutWithMillisAndTimezone = "2006-01-02T15:04:05.000-07.00"
+	// ISOLayoutWithMillisAndColonTimezone is the strict ISO 8601 offset some bank require
+	ISOLayoutWithMillisAndColonTimezone = "2006-01-02T15:04:05.000-07:00"
+)
 
 func FormatTimeToISO7(t time.Time) string {
 	location := time.FixedZone("WIB", 7*60*60)
 	return t.In(location).Format(ISOLayoutWithMillisAndTimezone)
 }
 
+// FormatTimeToISO7Layout format t in WIB using the given layout
+func FormatTimeToISO7Layout(t time.Time, layout string) string {
+	location := time.FixedZone("WIB", 7*60*60)
+	return t.In(location).Format(layout)
+}
+
 func FormatISO7ToTime(value string) (time.Time, error) {
 	return time.Parse(ISOLayoutWithMillisAndTimezone, value)
 }
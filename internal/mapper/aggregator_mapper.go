@@ -0,0 +1,158 @@
+package mapper
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
+	"briefcash-inquiry/internal/helper/jsonhelper"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	aggregatorProviderType = "AGGREGATOR"
+	aggregatorAuthApiKey   = "api_key"
+
+	aggregatorStatusSuccess = "SUCCESS"
+	aggregatorStatusPending = "PENDING"
+	aggregatorStatusFailed  = "FAILED"
+)
+
+// aggregatorValidationStatus translate validation status of the aggregator into
+// the HTTP status our inquiry flow classify result with, pending validation is
+// kept inconclusive and settled later through the status check
+var aggregatorValidationStatus = map[string]int{
+	aggregatorStatusSuccess: http.StatusOK,
+	aggregatorStatusPending: http.StatusAccepted,
+	"INVALID_ACCOUNT":       http.StatusNotFound,
+	aggregatorStatusFailed:  http.StatusBadGateway,
+}
+
+// aggregatorProvider validate account through a payment aggregator for bank we
+// have no direct partnership with. It is selected by partner setting
+// provider_type = AGGREGATOR, so any partner row can point to an aggregator and
+// route long tail destination bank to it. The X-REFERENCE-ID header carry the
+// external id stored on the inquiry, the status check look the validation up by it
+type aggregatorProvider struct{}
+
+func NewAggregatorProvider() *aggregatorProvider {
+	return &aggregatorProvider{}
+}
+
+func (aggregator *aggregatorProvider) Code() string {
+	return aggregatorProviderType
+}
+
+func (aggregator *aggregatorProvider) Name() string {
+	return "AGGREGATOR"
+}
+
+func (aggregator *aggregatorProvider) UsesStaticAuth() bool {
+	return true
+}
+
+func (aggregator *aggregatorProvider) BuildBodyRequest(cfg *entity.BankConfig, req dto.InquiryRequest) []byte {
+	payload := dto.AggregatorInquiryRequest{
+		BankCode:      req.BankCode,
+		AccountNumber: req.BeneficiaryAccount,
+	}
+	return jsonhelper.WriteToJson(payload)
+}
+
+func (aggregator *aggregatorProvider) GetUrl(cfg *entity.BankConfig, req dto.InquiryRequest) string {
+	return cfg.ExternalInquiryURL
+}
+
+func (aggregator *aggregatorProvider) GetHeaders(cfg *entity.BankConfig, req dto.InquiryRequest, accessToken, externalId string, payload []byte) map[string]string {
+	return aggregatorHeaders(cfg, externalId)
+}
+
+func (aggregator *aggregatorProvider) MapResponse(cfg *entity.BankConfig, req dto.InquiryRequest, httpStatus int, bankResponse []byte) (BankResponseData, error) {
+	return mapAggregatorResponse(httpStatus, bankResponse)
+}
+
+// GetAccessToken is never called for static auth provider, it exist to
+// satisfy the provider contract
+func (aggregator *aggregatorProvider) GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	return dto.SNAPAccessToken{}, errors.New("aggregator authenticate with static credential, no access token is issued")
+}
+
+func (aggregator *aggregatorProvider) BuildStatusRequest(cfg *entity.BankConfig, inquiry *entity.Inquiry) []byte {
+	return jsonhelper.WriteToJson(dto.AggregatorStatusRequest{ReferenceId: inquiry.ExternalId})
+}
+
+func (aggregator *aggregatorProvider) GetStatusUrl(cfg *entity.BankConfig) string {
+	return cfg.StatusInquiryURL
+}
+
+func (aggregator *aggregatorProvider) GetStatusHeaders(cfg *entity.BankConfig, accessToken, externalId string, payload []byte) map[string]string {
+	return aggregatorHeaders(cfg, externalId)
+}
+
+func (aggregator *aggregatorProvider) MapStatusResponse(cfg *entity.BankConfig, httpStatus int, bankResponse []byte) (BankResponseData, error) {
+	data, err := mapAggregatorResponse(httpStatus, bankResponse)
+	if err != nil {
+		return BankResponseData{}, err
+	}
+	// invalid account keep its HTTP 404 and is settled as not found, failed
+	// validation is a settled bank error rather than an inconclusive one
+	switch data.ResponseCode {
+	case aggregatorStatusSuccess:
+		data.TransactionStatus = snapTransactionSuccess
+	case aggregatorStatusFailed:
+		data.TransactionStatus = snapTransactionFailed
+		data.HttpStatus = http.StatusOK
+	default:
+		data.TransactionStatus = data.ResponseCode
+	}
+	return data, nil
+}
+
+func aggregatorHeaders(cfg *entity.BankConfig, externalId string) map[string]string {
+	headers := map[string]string{
+		"Content-Type":   "application/json",
+		"X-REFERENCE-ID": externalId,
+	}
+	if cfg.AuthScheme == aggregatorAuthApiKey {
+		headers["X-API-KEY"] = cfg.ClientKey
+	} else {
+		credential := base64.StdEncoding.EncodeToString([]byte(cfg.ClientKey + ":" + cfg.ClientSecret))
+		headers["Authorization"] = "Basic " + credential
+	}
+	return headers
+}
+
+func mapAggregatorResponse(httpStatus int, bankResponse []byte) (BankResponseData, error) {
+	var respDto dto.AggregatorResponse
+	if err := json.Unmarshal(bankResponse, &respDto); err != nil {
+		return BankResponseData{}, err
+	}
+
+	if respDto.Data != nil {
+		return codeResponseData(aggregatorValidationStatus, respDto.Data.Status, respDto.Data.FailureReason, respDto.Data.AccountHolderName), nil
+	}
+
+	if respDto.Error != nil {
+		// error envelope is classified by the HTTP status it came with,
+		// one sent with HTTP 200 is still a failure
+		data := BankResponseData{
+			ResponseCode:    respDto.Error.Code,
+			ResponseMessage: respDto.Error.Message,
+		}
+		if httpStatus == http.StatusOK {
+			data.HttpStatus = http.StatusInternalServerError
+		}
+		return data, nil
+	}
+
+	if httpStatus != http.StatusOK {
+		return BankResponseData{}, nil
+	}
+	return BankResponseData{}, fmt.Errorf("aggregator response without data nor error envelope")
+}
@@ -1,6 +1,19 @@
 package mapper
 
 type BankResponseData struct {
-	AccountName     string
-	ResponseMessage string
+	AccountName       string
+	ResponseCode      string
+	ResponseMessage   string
+	TransactionStatus string
+	// HttpStatus is the HTTP status equivalent of ResponseCode, set by provider
+	// of bank answering business failure with HTTP 200, zero keep the real status
+	HttpStatus int
+}
+
+// EffectiveStatus return the status the inquiry result should be classified with
+func (d BankResponseData) EffectiveStatus(httpStatus int) int {
+	if d.HttpStatus != 0 {
+		return d.HttpStatus
+	}
+	return httpStatus
 }
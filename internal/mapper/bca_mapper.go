@@ -4,42 +4,45 @@ import (
 	"briefcash-inquiry/internal/authorization"
 	"briefcash-inquiry/internal/dto"
 	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
 	"briefcash-inquiry/internal/helper/jsonhelper"
 	"briefcash-inquiry/internal/helper/timehelper"
+	"context"
 	"encoding/json"
 	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
-type bcaClientRequest struct {
-	cfg *entity.BankConfig
-	req dto.InquiryRequest
+const bcaBankCode = "014"
+
+type bcaProvider struct {
+	snapStatusInquiry
 }
 
-type bcaClientResponse struct {
-	cfg *entity.BankConfig
+func NewBcaProvider() *bcaProvider {
+	return &bcaProvider{}
 }
 
-func NewBcaClientRequest(cfg *entity.BankConfig, req dto.InquiryRequest) *bcaClientRequest {
-	return &bcaClientRequest{
-		cfg: cfg, req: req,
-	}
+func (bca *bcaProvider) Code() string {
+	return bcaBankCode
 }
 
-func NewBcaClientResponse(cfg *entity.BankConfig) *bcaClientResponse {
-	return &bcaClientResponse{cfg: cfg}
+func (bca *bcaProvider) Name() string {
+	return "BCA"
 }
 
-func (bca *bcaClientRequest) BuildBodyRequest() []byte {
-	if bca.req.BankCode == "014" {
+func (bca *bcaProvider) BuildBodyRequest(cfg *entity.BankConfig, req dto.InquiryRequest) []byte {
+	if req.BankCode == bcaBankCode {
 		request := dto.BCAInternalInquiryRequest{
-			PartnerReferenceNo:   bca.req.PartnerReferenceNo,
-			BeneficiaryAccountNo: bca.req.BeneficiaryAccount,
+			PartnerReferenceNo:   req.PartnerReferenceNo,
+			BeneficiaryAccountNo: req.BeneficiaryAccount,
 		}
 		return jsonhelper.WriteToJson(request)
 	} else {
 		additionalInfo := dto.BCAAdditionalInfo{
 			InquiryService: func() string {
-				if bca.req.Type == "bifast" {
+				if req.Type == "bifast" {
 					return "2"
 				} else {
 					return "1"
@@ -47,35 +50,29 @@ func (bca *bcaClientRequest) BuildBodyRequest() []byte {
 			}(),
 		}
 		request := dto.BCAExternalInquiryRequest{
-			BeneficiaryBankCode:  bca.req.BankCode,
-			BeneficiaryAccountNo: bca.req.BeneficiaryAccount,
-			PartnerReferenceNo:   bca.req.PartnerReferenceNo,
+			BeneficiaryBankCode:  req.BankCode,
+			BeneficiaryAccountNo: req.BeneficiaryAccount,
+			PartnerReferenceNo:   req.PartnerReferenceNo,
 			AdditionalInfo:       additionalInfo,
 		}
 		return jsonhelper.WriteToJson(request)
 	}
 }
 
-func (bca *bcaClientRequest) GetUrl() string {
+func (bca *bcaProvider) GetUrl(cfg *entity.BankConfig, req dto.InquiryRequest) string {
 	var url string
-	if bca.req.BankCode == "014" {
-		url = bca.cfg.InternalInquiryURL
+	if req.BankCode == bcaBankCode {
+		url = cfg.InternalInquiryURL
 	} else {
-		url = bca.cfg.ExternalInquiryURL
+		url = cfg.ExternalInquiryURL
 	}
 	return url
 }
 
-func (bca *bcaClientRequest) GetHeaders(accessToken, externalId string, cfg *entity.BankConfig, payload []byte) map[string]string {
+func (bca *bcaProvider) GetHeaders(cfg *entity.BankConfig, req dto.InquiryRequest, accessToken, externalId string, payload []byte) map[string]string {
 	hexPayload := authorization.HashSHA256Hex(payload)
 	timestamp := timehelper.FormatTimeToISO7(time.Now())
-	endpoint := func() string {
-		if cfg.BankCode == "014" {
-			return cfg.InternalInquiryURL
-		} else {
-			return cfg.ExternalInquiryURL
-		}
-	}()
+	endpoint := bca.GetUrl(cfg, req)
 	signature := authorization.HashSignature("POST", endpoint, accessToken, hexPayload, timestamp, cfg.ClientSecret)
 	return map[string]string{
 		"Content-Type":  "application/json",
@@ -88,11 +85,18 @@ func (bca *bcaClientRequest) GetHeaders(accessToken, externalId string, cfg *ent
 	}
 }
 
-func (bca *bcaClientResponse) MapResponse(bankResponse []byte) BankResponseData {
+func (bca *bcaProvider) MapResponse(cfg *entity.BankConfig, req dto.InquiryRequest, httpStatus int, bankResponse []byte) (BankResponseData, error) {
 	var inquiryResponse dto.BCAInquiryResponse
-	json.Unmarshal(bankResponse, &inquiryResponse)
+	if err := json.Unmarshal(bankResponse, &inquiryResponse); err != nil {
+		return BankResponseData{}, err
+	}
 	return BankResponseData{
 		AccountName:     inquiryResponse.BeneficiaryAccountName,
+		ResponseCode:    inquiryResponse.ResponseCode,
 		ResponseMessage: inquiryResponse.ResponseMessage,
-	}
+	}, nil
+}
+
+func (bca *bcaProvider) GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	return authorization.GetAccessToken(ctx, client, cfg, log)
 }
@@ -0,0 +1,131 @@
+package mapper
+
+import (
+	"briefcash-inquiry/internal/authorization"
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
+	"briefcash-inquiry/internal/helper/jsonhelper"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	bniBankCode        = "009"
+	bniResponseSuccess = "0001"
+)
+
+// bniResponseStatus translate BNI host-to-host response code into the HTTP
+// status our inquiry flow classify result with, BNI answer most business
+// failure with HTTP 200 so the code is the only signal
+var bniResponseStatus = map[string]int{
+	bniResponseSuccess: http.StatusOK,
+	"0101":             http.StatusBadRequest,          // invalid parameter
+	"0102":             http.StatusNotFound,            // account not found
+	"0103":             http.StatusNotFound,            // account closed or inactive
+	"0105":             http.StatusConflict,            // duplicate customer reference
+	"0201":             http.StatusUnauthorized,        // invalid or expired access token
+	"0202":             http.StatusBadRequest,          // invalid signature
+	"0203":             http.StatusForbidden,           // client not allowed to access service
+	"0901":             http.StatusGatewayTimeout,      // timeout on BNI core
+	"0902":             http.StatusServiceUnavailable,  // destination bank unavailable
+	"0999":             http.StatusInternalServerError, // general system error
+}
+
+type bniProvider struct{}
+
+func NewBniProvider() *bniProvider {
+	return &bniProvider{}
+}
+
+func (bni *bniProvider) Code() string {
+	return bniBankCode
+}
+
+func (bni *bniProvider) Name() string {
+	return "BNI"
+}
+
+// BuildBodyRequest sign the body itself, BNI expect a JWT of the payload
+// without the signature field in the signature field
+func (bni *bniProvider) BuildBodyRequest(cfg *entity.BankConfig, req dto.InquiryRequest) []byte {
+	if req.BankCode == bniBankCode {
+		payload := dto.BNIInternalInquiryRequest{
+			ClientId:  cfg.PartnerId,
+			AccountNo: req.BeneficiaryAccount,
+		}
+		payload.Signature = authorization.SignJWTHS256(jsonhelper.WriteToJson(payload), cfg.ClientSecret)
+		return jsonhelper.WriteToJson(payload)
+	}
+
+	payload := dto.BNIExternalInquiryRequest{
+		ClientId:                cfg.PartnerId,
+		CustomerReferenceNumber: req.PartnerReferenceNo,
+		AccountNum:              cfg.SourceAccountNo,
+		DestinationBankCode:     req.BankCode,
+		DestinationAccountNum:   req.BeneficiaryAccount,
+	}
+	payload.Signature = authorization.SignJWTHS256(jsonhelper.WriteToJson(payload), cfg.ClientSecret)
+	return jsonhelper.WriteToJson(payload)
+}
+
+func (bni *bniProvider) GetUrl(cfg *entity.BankConfig, req dto.InquiryRequest) string {
+	if req.BankCode == bniBankCode {
+		return cfg.InternalInquiryURL
+	}
+	return cfg.ExternalInquiryURL
+}
+
+func (bni *bniProvider) GetHeaders(cfg *entity.BankConfig, req dto.InquiryRequest, accessToken, externalId string, payload []byte) map[string]string {
+	return map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer " + accessToken,
+	}
+}
+
+func (bni *bniProvider) MapResponse(cfg *entity.BankConfig, req dto.InquiryRequest, httpStatus int, bankResponse []byte) (BankResponseData, error) {
+	var errDto dto.BNIErrorResponse
+	if err := json.Unmarshal(bankResponse, &errDto); err != nil {
+		return BankResponseData{}, err
+	}
+	if params := errDto.Result.Parameters; params.ResponseCode != "" {
+		return codeResponseData(bniResponseStatus, params.ResponseCode, bniMessage(params.ResponseMessage, params.ErrorMessage), ""), nil
+	}
+
+	if req.BankCode == bniBankCode {
+		var respDto dto.BNIInternalInquiryResponse
+		if err := json.Unmarshal(bankResponse, &respDto); err != nil {
+			return BankResponseData{}, err
+		}
+		params := respDto.Result.Parameters
+		if params.ResponseCode == "" {
+			return BankResponseData{}, fmt.Errorf("unexpected BNI response envelope, http code %d", httpStatus)
+		}
+		return codeResponseData(bniResponseStatus, params.ResponseCode, bniMessage(params.ResponseMessage, params.ErrorMessage), params.CustomerName), nil
+	}
+
+	var respDto dto.BNIExternalInquiryResponse
+	if err := json.Unmarshal(bankResponse, &respDto); err != nil {
+		return BankResponseData{}, err
+	}
+	params := respDto.Result.Parameters
+	if params.ResponseCode == "" {
+		return BankResponseData{}, fmt.Errorf("unexpected BNI response envelope, http code %d", httpStatus)
+	}
+	return codeResponseData(bniResponseStatus, params.ResponseCode, bniMessage(params.ResponseMessage, params.ErrorMessage), params.DestinationAccountName), nil
+}
+
+func (bni *bniProvider) GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	return authorization.GetOAuthAccessToken(ctx, client, cfg, nil, log)
+}
+
+func bniMessage(responseMessage, errorMessage string) string {
+	if errorMessage != "" {
+		return errorMessage
+	}
+	return responseMessage
+}
@@ -4,40 +4,39 @@ import (
 	"briefcash-inquiry/internal/authorization"
 	"briefcash-inquiry/internal/dto"
 	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
 	"briefcash-inquiry/internal/helper/jsonhelper"
 	"briefcash-inquiry/internal/helper/timehelper"
+	"context"
 	"encoding/json"
 	"net/http"
 	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
-type briClientRequest struct {
-	cfg *entity.BankConfig
-	req dto.InquiryRequest
+const briBankCode = "002"
+
+type briProvider struct {
+	snapStatusInquiry
 }
 
-type briClientResponse struct {
-	cfg        *entity.BankConfig
-	httpStatus int
+func NewBriProvider() *briProvider {
+	return &briProvider{}
 }
 
-func NewBriClientRequest(cfg *entity.BankConfig, req dto.InquiryRequest) *briClientRequest {
-	return &briClientRequest{
-		cfg: cfg, req: req,
-	}
+func (bri *briProvider) Code() string {
+	return briBankCode
 }
 
-func NewBriClientResponse(cfg *entity.BankConfig, httpStatus int) *briClientResponse {
-	return &briClientResponse{
-		cfg:        cfg,
-		httpStatus: httpStatus,
-	}
+func (bri *briProvider) Name() string {
+	return "BRI"
 }
 
-func (bri *briClientRequest) BuildBodyRequest() []byte {
-	if bri.req.BankCode == "002" {
+func (bri *briProvider) BuildBodyRequest(cfg *entity.BankConfig, req dto.InquiryRequest) []byte {
+	if req.BankCode == briBankCode {
 		payload := dto.BRIInternalInquiryRequest{
-			BeneficiaryAccountNo: bri.req.BeneficiaryAccount,
+			BeneficiaryAccountNo: req.BeneficiaryAccount,
 			AdditionalInfo: map[string]string{
 				"channel":  "",
 				"deviceId": "",
@@ -46,11 +45,11 @@ func (bri *briClientRequest) BuildBodyRequest() []byte {
 		return jsonhelper.WriteToJson(payload)
 	} else {
 		payload := dto.BRIExternalInquiryRequest{
-			BeneficiaryBankCode:  bri.req.BankCode,
-			BeneficiaryAccountNo: bri.req.BeneficiaryAccount,
+			BeneficiaryBankCode:  req.BankCode,
+			BeneficiaryAccountNo: req.BeneficiaryAccount,
 			AdditionalInfo: map[string]string{
 				"serviceCode": func() string {
-					if bri.req.Type == "bifast" {
+					if req.Type == "bifast" {
 						return "81"
 					} else {
 						return "16"
@@ -64,19 +63,16 @@ func (bri *briClientRequest) BuildBodyRequest() []byte {
 	}
 }
 
-func (bri *briClientRequest) GetUrl() string {
-	return bri.cfg.InternalInquiryURL
+func (bri *briProvider) GetUrl(cfg *entity.BankConfig, req dto.InquiryRequest) string {
+	if req.BankCode == briBankCode {
+		return cfg.InternalInquiryURL
+	}
+	return cfg.ExternalInquiryURL
 }
 
-func (bri *briClientRequest) GetHeaders(accessToken, externalId string, cfg *entity.BankConfig, payload []byte) map[string]string {
+func (bri *briProvider) GetHeaders(cfg *entity.BankConfig, req dto.InquiryRequest, accessToken, externalId string, payload []byte) map[string]string {
 	hexPaylod := authorization.HashSHA256Hex(payload)
-	endpoint := func() string {
-		if cfg.BankCode == "002" {
-			return cfg.InternalInquiryURL
-		} else {
-			return cfg.ExternalInquiryURL
-		}
-	}()
+	endpoint := bri.GetUrl(cfg, req)
 	timestamp := timehelper.FormatTimeToISO7(time.Now())
 	signature := authorization.HashSignature("POST", endpoint, accessToken, hexPaylod, timestamp, cfg.ClientSecret)
 	return map[string]string{
@@ -90,30 +86,43 @@ func (bri *briClientRequest) GetHeaders(accessToken, externalId string, cfg *ent
 	}
 }
 
-func (bri *briClientResponse) MapResponse(bankResponse []byte) BankResponseData {
+func (bri *briProvider) MapResponse(cfg *entity.BankConfig, req dto.InquiryRequest, httpStatus int, bankResponse []byte) (BankResponseData, error) {
 
-	if bri.httpStatus != http.StatusOK {
+	if httpStatus != http.StatusOK {
 		var resDto dto.BRIErrorResponse
-		json.Unmarshal(bankResponse, &resDto)
+		if err := json.Unmarshal(bankResponse, &resDto); err != nil {
+			return BankResponseData{}, err
+		}
 		return BankResponseData{
+			ResponseCode:    resDto.ResponseCode,
 			ResponseMessage: resDto.ResponseMessage,
 			AccountName:     "",
-		}
+		}, nil
 	}
 
-	if bri.cfg.BankCode == "002" {
+	if req.BankCode == briBankCode {
 		var resDto dto.BRIInternalInquiryResponse
-		json.Unmarshal(bankResponse, &resDto)
+		if err := json.Unmarshal(bankResponse, &resDto); err != nil {
+			return BankResponseData{}, err
+		}
 		return BankResponseData{
 			AccountName:     resDto.BeneficiaryAccountName,
+			ResponseCode:    resDto.ResponseCode,
 			ResponseMessage: resDto.ResponseMessage,
-		}
+		}, nil
 	} else {
 		var resDto dto.BRIExternalInquiryResponse
-		json.Unmarshal(bankResponse, &resDto)
+		if err := json.Unmarshal(bankResponse, &resDto); err != nil {
+			return BankResponseData{}, err
+		}
 		return BankResponseData{
 			AccountName:     resDto.BeneficiaryAccountName,
+			ResponseCode:    resDto.ResponseCode,
 			ResponseMessage: resDto.ResponseMessage,
-		}
+		}, nil
 	}
 }
+
+func (bri *briProvider) GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	return authorization.GetAccessToken(ctx, client, cfg, log)
+}
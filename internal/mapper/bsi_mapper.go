@@ -0,0 +1,57 @@
+package mapper
+
+import (
+	"briefcash-inquiry/internal/authorization"
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
+	"briefcash-inquiry/internal/helper/timehelper"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const bsiBankCode = "451"
+
+type bsiProvider struct {
+	snapStatusInquiry
+}
+
+func NewBsiProvider() *bsiProvider {
+	return &bsiProvider{}
+}
+
+func (bsi *bsiProvider) Code() string {
+	return bsiBankCode
+}
+
+func (bsi *bsiProvider) Name() string {
+	return "BSI"
+}
+
+func (bsi *bsiProvider) BuildBodyRequest(cfg *entity.BankConfig, req dto.InquiryRequest) []byte {
+	return snapInquiryBody(req, bsiBankCode, configAdditionalInfo(cfg, true), configAdditionalInfo(cfg, false))
+}
+
+func (bsi *bsiProvider) GetUrl(cfg *entity.BankConfig, req dto.InquiryRequest) string {
+	if req.BankCode == bsiBankCode {
+		return cfg.InternalInquiryURL
+	}
+	return cfg.ExternalInquiryURL
+}
+
+func (bsi *bsiProvider) GetHeaders(cfg *entity.BankConfig, req dto.InquiryRequest, accessToken, externalId string, payload []byte) map[string]string {
+	hexPayload := authorization.HashSHA256Hex(payload)
+	timestamp := timehelper.FormatTimeToISO7(time.Now())
+	signature := authorization.HashSignature("POST", bsi.GetUrl(cfg, req), accessToken, hexPayload, timestamp, cfg.ClientSecret)
+	return snapHeaders(cfg, accessToken, externalId, timestamp, signature)
+}
+
+func (bsi *bsiProvider) MapResponse(cfg *entity.BankConfig, req dto.InquiryRequest, httpStatus int, bankResponse []byte) (BankResponseData, error) {
+	return snapInquiryResponse(bankResponse)
+}
+
+func (bsi *bsiProvider) GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	return authorization.GetAccessToken(ctx, client, cfg, log)
+}
@@ -0,0 +1,69 @@
+package mapper
+
+import (
+	"briefcash-inquiry/internal/authorization"
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
+	"briefcash-inquiry/internal/helper/timehelper"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const btnBankCode = "200"
+
+type btnProvider struct {
+	snapStatusInquiry
+}
+
+func NewBtnProvider() *btnProvider {
+	return &btnProvider{}
+}
+
+func (btn *btnProvider) Code() string {
+	return btnBankCode
+}
+
+func (btn *btnProvider) Name() string {
+	return "BTN"
+}
+
+func (btn *btnProvider) BuildBodyRequest(cfg *entity.BankConfig, req dto.InquiryRequest) []byte {
+	return snapInquiryBody(req, btnBankCode, configAdditionalInfo(cfg, true), configAdditionalInfo(cfg, false))
+}
+
+func (btn *btnProvider) GetUrl(cfg *entity.BankConfig, req dto.InquiryRequest) string {
+	if req.BankCode == btnBankCode {
+		return cfg.InternalInquiryURL
+	}
+	return cfg.ExternalInquiryURL
+}
+
+// GetHeaders follow the strict SNAP symmetric signature, HMAC-SHA512 in base64
+// over the relative path instead of the hex HMAC-SHA256 most partner accept
+func (btn *btnProvider) GetHeaders(cfg *entity.BankConfig, req dto.InquiryRequest, accessToken, externalId string, payload []byte) map[string]string {
+	return btnHeaders(cfg, btn.GetUrl(cfg, req), accessToken, externalId, payload)
+}
+
+// GetStatusHeaders sign the status request the same way as the inquiry, the
+// SHA256 signature of the embedded SNAP status is rejected by BTN
+func (btn *btnProvider) GetStatusHeaders(cfg *entity.BankConfig, accessToken, externalId string, payload []byte) map[string]string {
+	return btnHeaders(cfg, btn.GetStatusUrl(cfg), accessToken, externalId, payload)
+}
+
+func (btn *btnProvider) MapResponse(cfg *entity.BankConfig, req dto.InquiryRequest, httpStatus int, bankResponse []byte) (BankResponseData, error) {
+	return snapInquiryResponse(bankResponse)
+}
+
+func (btn *btnProvider) GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	return authorization.GetAccessToken(ctx, client, cfg, log)
+}
+
+func btnHeaders(cfg *entity.BankConfig, endpoint, accessToken, externalId string, payload []byte) map[string]string {
+	hexPayload := authorization.HashSHA256Hex(payload)
+	timestamp := timehelper.FormatTimeToISO7(time.Now())
+	signature := authorization.HashSignatureSHA512("POST", relativeUrl(endpoint), accessToken, hexPayload, timestamp, cfg.ClientSecret)
+	return snapHeaders(cfg, accessToken, externalId, timestamp, signature)
+}
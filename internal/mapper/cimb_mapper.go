@@ -4,49 +4,50 @@ import (
 	"briefcash-inquiry/internal/authorization"
 	"briefcash-inquiry/internal/dto"
 	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
 	"briefcash-inquiry/internal/helper/jsonhelper"
 	"briefcash-inquiry/internal/helper/timehelper"
+	"context"
 	"encoding/json"
 	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
-type cimbClientRequest struct {
-	cfg *entity.BankConfig
-	req dto.InquiryRequest
+const cimbBankCode = "022"
+
+type cimbProvider struct {
+	snapStatusInquiry
 }
 
-type cimbClientResponse struct {
-	cfg *entity.BankConfig
+func NewCimbProvider() *cimbProvider {
+	return &cimbProvider{}
 }
 
-func NewCimbClientRequest(cfg *entity.BankConfig, req dto.InquiryRequest) *cimbClientRequest {
-	return &cimbClientRequest{
-		cfg: cfg, req: req,
-	}
+func (cimb *cimbProvider) Code() string {
+	return cimbBankCode
 }
 
-func NewCimbClientResponse(cfg *entity.BankConfig) *cimbClientResponse {
-	return &cimbClientResponse{
-		cfg: cfg,
-	}
+func (cimb *cimbProvider) Name() string {
+	return "CIMB"
 }
 
-func (cimb *cimbClientRequest) BuildBodyRequest() []byte {
-	if cimb.req.BankCode == "022" {
+func (cimb *cimbProvider) BuildBodyRequest(cfg *entity.BankConfig, req dto.InquiryRequest) []byte {
+	if req.BankCode == cimbBankCode {
 		payload := dto.CimbInternalInquiryRequest{
-			PartnerReferenceNo:   cimb.req.PartnerReferenceNo,
-			BeneficiaryAccountNo: cimb.req.BeneficiaryAccount,
+			PartnerReferenceNo:   req.PartnerReferenceNo,
+			BeneficiaryAccountNo: req.BeneficiaryAccount,
 			AdditionalInfo:       make(map[string]string),
 		}
 		return jsonhelper.WriteToJson(payload)
 	} else {
 		payload := dto.CimbExternalInquiryRequest{
-			BeneficiaryBankCode:  cimb.req.BankCode,
-			BeneficiaryAccountNo: cimb.req.BeneficiaryAccount,
-			PartnerReferenceNo:   cimb.req.PartnerReferenceNo,
+			BeneficiaryBankCode:  req.BankCode,
+			BeneficiaryAccountNo: req.BeneficiaryAccount,
+			PartnerReferenceNo:   req.PartnerReferenceNo,
 			AdditionalInfo: map[string]string{
 				"trxType": func() string {
-					if cimb.req.Type == "bifast" {
+					if req.Type == "bifast" {
 						return "02"
 					} else {
 						return "01"
@@ -61,25 +62,19 @@ func (cimb *cimbClientRequest) BuildBodyRequest() []byte {
 	}
 }
 
-func (cimb *cimbClientRequest) GetUrl() string {
+func (cimb *cimbProvider) GetUrl(cfg *entity.BankConfig, req dto.InquiryRequest) string {
 	var url string
-	if cimb.req.BankCode == "022" {
-		url = cimb.cfg.InternalInquiryURL
+	if req.BankCode == cimbBankCode {
+		url = cfg.InternalInquiryURL
 	} else {
-		url = cimb.cfg.ExternalInquiryURL
+		url = cfg.ExternalInquiryURL
 	}
 	return url
 }
 
-func (cimb *cimbClientRequest) GetHeaders(accessToken, externalId string, cfg *entity.BankConfig, payload []byte) map[string]string {
+func (cimb *cimbProvider) GetHeaders(cfg *entity.BankConfig, req dto.InquiryRequest, accessToken, externalId string, payload []byte) map[string]string {
 	hexPayload := authorization.HashSHA256Hex(payload)
-	endpoint := func() string {
-		if cfg.BankCode == "022" {
-			return cfg.InternalInquiryURL
-		} else {
-			return cfg.ExternalInquiryURL
-		}
-	}()
+	endpoint := cimb.GetUrl(cfg, req)
 	timestamp := timehelper.FormatTimeToISO7(time.Now())
 	signature := authorization.HashSignature("POST", endpoint, accessToken, hexPayload, timestamp, cfg.ClientSecret)
 	return map[string]string{
@@ -93,20 +88,30 @@ func (cimb *cimbClientRequest) GetHeaders(accessToken, externalId string, cfg *e
 	}
 }
 
-func (cimb *cimbClientResponse) MapResponse(bankResponse []byte) BankResponseData {
-	if cimb.cfg.BankCode == "022" {
+func (cimb *cimbProvider) MapResponse(cfg *entity.BankConfig, req dto.InquiryRequest, httpStatus int, bankResponse []byte) (BankResponseData, error) {
+	if req.BankCode == cimbBankCode {
 		var respDto dto.CimbInternalInquiryResponse
-		json.Unmarshal(bankResponse, &respDto)
+		if err := json.Unmarshal(bankResponse, &respDto); err != nil {
+			return BankResponseData{}, err
+		}
 		return BankResponseData{
 			AccountName:     respDto.BeneficiaryAccountName,
+			ResponseCode:    respDto.ResponseCode,
 			ResponseMessage: respDto.ResponseMessage,
-		}
+		}, nil
 	} else {
 		var respDto dto.CimbExternalInquiryResponse
-		json.Unmarshal(bankResponse, &respDto)
+		if err := json.Unmarshal(bankResponse, &respDto); err != nil {
+			return BankResponseData{}, err
+		}
 		return BankResponseData{
 			AccountName:     respDto.BeneficiaryAccountName,
+			ResponseCode:    respDto.ResponseCode,
 			ResponseMessage: respDto.ResponseMessage,
-		}
+		}, nil
 	}
 }
+
+func (cimb *cimbProvider) GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	return authorization.GetAccessToken(ctx, client, cfg, log)
+}
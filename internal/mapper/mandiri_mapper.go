@@ -0,0 +1,73 @@
+package mapper
+
+import (
+	"briefcash-inquiry/internal/authorization"
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
+	"briefcash-inquiry/internal/helper/timehelper"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const mandiriBankCode = "008"
+
+// Mandiri reject the SNAP default "-07.00" offset, every timestamp it receive
+// (token and transactional) must use the strict ISO 8601 "+07:00" form
+var mandiriTokenOption = authorization.TokenRequestOption{
+	TimestampLayout: timehelper.ISOLayoutWithMillisAndColonTimezone,
+	GrantTypeField:  "grantType",
+}
+
+type mandiriProvider struct{}
+
+func NewMandiriProvider() *mandiriProvider {
+	return &mandiriProvider{}
+}
+
+func (mandiri *mandiriProvider) Code() string {
+	return mandiriBankCode
+}
+
+func (mandiri *mandiriProvider) Name() string {
+	return "MANDIRI"
+}
+
+func (mandiri *mandiriProvider) BuildBodyRequest(cfg *entity.BankConfig, req dto.InquiryRequest) []byte {
+	transferType := "ONLINE"
+	if req.Type == "bifast" {
+		transferType = "BIFAST"
+	}
+	return snapInquiryBody(req, mandiriBankCode, nil, map[string]string{"transferType": transferType})
+}
+
+func (mandiri *mandiriProvider) GetUrl(cfg *entity.BankConfig, req dto.InquiryRequest) string {
+	if req.BankCode == mandiriBankCode {
+		return cfg.InternalInquiryURL
+	}
+	return cfg.ExternalInquiryURL
+}
+
+// RequiresNumericExternalId is set because Mandiri reject X-EXTERNAL-ID
+// containing anything but digits, merchant reference may be alphanumeric
+func (mandiri *mandiriProvider) RequiresNumericExternalId() bool {
+	return true
+}
+
+// GetHeaders sign only the relative path of the endpoint with HMAC-SHA512
+func (mandiri *mandiriProvider) GetHeaders(cfg *entity.BankConfig, req dto.InquiryRequest, accessToken, externalId string, payload []byte) map[string]string {
+	hexPayload := authorization.HashSHA256Hex(payload)
+	timestamp := timehelper.FormatTimeToISO7Layout(time.Now(), timehelper.ISOLayoutWithMillisAndColonTimezone)
+	signature := authorization.HashSignatureSHA512("POST", relativeUrl(mandiri.GetUrl(cfg, req)), accessToken, hexPayload, timestamp, cfg.ClientSecret)
+	return snapHeaders(cfg, accessToken, externalId, timestamp, signature)
+}
+
+func (mandiri *mandiriProvider) MapResponse(cfg *entity.BankConfig, req dto.InquiryRequest, httpStatus int, bankResponse []byte) (BankResponseData, error) {
+	return snapInquiryResponse(bankResponse)
+}
+
+func (mandiri *mandiriProvider) GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	return authorization.GetAccessTokenWithOption(ctx, client, cfg, mandiriTokenOption, log)
+}
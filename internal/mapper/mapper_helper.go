@@ -0,0 +1,100 @@
+package mapper
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/jsonhelper"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// relativeUrl strip scheme and host for bank verifying the signature against
+// the path only, configured url may be either absolute or relative
+func relativeUrl(endpoint string) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Path == "" {
+		return endpoint
+	}
+	if parsed.RawQuery != "" {
+		return parsed.Path + "?" + parsed.RawQuery
+	}
+	return parsed.Path
+}
+
+// configAdditionalInfo return the additionalInfo configured for the partner bank,
+// malformed setting already fail the partner config load so error is not expected here
+func configAdditionalInfo(cfg *entity.BankConfig, internal bool) map[string]string {
+	info, err := cfg.InquiryAdditionalInfo(internal)
+	if err != nil {
+		return make(map[string]string)
+	}
+	return info
+}
+
+// codeResponseData build the result of bank answering HTTP 200 for every
+// outcome, statusMap translate the bank code into the equivalent HTTP status.
+// Account name is only kept for a successful code
+func codeResponseData(statusMap map[string]int, code, message, accountName string) BankResponseData {
+	status, ok := statusMap[code]
+	if !ok {
+		// unlisted code never mean success, keep it inconclusive
+		status = http.StatusInternalServerError
+	}
+	if status != http.StatusOK {
+		accountName = ""
+	}
+	return BankResponseData{
+		AccountName:     accountName,
+		ResponseCode:    code,
+		ResponseMessage: message,
+		HttpStatus:      status,
+	}
+}
+
+// snapInquiryBody build the SNAP standard inquiry payload, ownBankCode decide
+// between the internal and the external (interbank) request and its additionalInfo
+func snapInquiryBody(req dto.InquiryRequest, ownBankCode string, internalInfo, externalInfo map[string]string) []byte {
+	if req.BankCode == ownBankCode {
+		payload := dto.SNAPInternalInquiryRequest{
+			PartnerReferenceNo:   req.PartnerReferenceNo,
+			BeneficiaryAccountNo: req.BeneficiaryAccount,
+			AdditionalInfo:       internalInfo,
+		}
+		return jsonhelper.WriteToJson(payload)
+	}
+
+	payload := dto.SNAPExternalInquiryRequest{
+		PartnerReferenceNo:   req.PartnerReferenceNo,
+		BeneficiaryBankCode:  req.BankCode,
+		BeneficiaryAccountNo: req.BeneficiaryAccount,
+		AdditionalInfo:       externalInfo,
+	}
+	return jsonhelper.WriteToJson(payload)
+}
+
+// snapInquiryResponse read SNAP inquiry answer, error answer carry the same
+// response code and message so one shape serve every status
+func snapInquiryResponse(bankResponse []byte) (BankResponseData, error) {
+	var respDto dto.SNAPInquiryResponse
+	if err := json.Unmarshal(bankResponse, &respDto); err != nil {
+		return BankResponseData{}, err
+	}
+	return BankResponseData{
+		AccountName:     respDto.BeneficiaryAccountName,
+		ResponseCode:    respDto.ResponseCode,
+		ResponseMessage: respDto.ResponseMessage,
+	}, nil
+}
+
+func snapHeaders(cfg *entity.BankConfig, accessToken, externalId, timestamp, signature string) map[string]string {
+	return map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer " + accessToken,
+		"X-TIMESTAMP":   timestamp,
+		"X-SIGNATURE":   signature,
+		"X-PARTNER-ID":  cfg.PartnerId,
+		"X-EXTERNAL-ID": externalId,
+		"CHANNEL-ID":    cfg.ChannelId,
+	}
+}
@@ -1,89 +1,147 @@
 package mapper
 
 import (
+	"briefcash-inquiry/internal/authorization"
 	"briefcash-inquiry/internal/dto"
 	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
 	"briefcash-inquiry/internal/helper/jsonhelper"
 	"briefcash-inquiry/internal/helper/timehelper"
+	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	permataBankCode       = "013"
+	permataStatusSuccess  = "00"
+	permataTokenGrantBody = "grant_type=client_credentials"
 )
 
-type permataClientRequest struct {
-	cfg *entity.BankConfig
-	req dto.InquiryRequest
+// permataResponseStatus translate Permata StatusCode into the HTTP status our
+// inquiry flow classify result with, Permata answer business failure with HTTP 200
+var permataResponseStatus = map[string]int{
+	permataStatusSuccess: http.StatusOK,
+	"14":                 http.StatusNotFound,            // invalid account
+	"76":                 http.StatusNotFound,            // invalid destination account
+	"30":                 http.StatusBadRequest,          // format error
+	"94":                 http.StatusConflict,            // duplicate transmission
+	"68":                 http.StatusGatewayTimeout,      // response received too late
+	"91":                 http.StatusServiceUnavailable,  // destination bank unavailable
+	"96":                 http.StatusInternalServerError, // system malfunction
 }
 
-type permataClientResponse struct {
-	cfg *entity.BankConfig
+type permataProvider struct{}
+
+func NewPermataProvider() *permataProvider {
+	return &permataProvider{}
 }
 
-func NewPermataClientRequest(cfg *entity.BankConfig, req dto.InquiryRequest) *permataClientRequest {
-	return &permataClientRequest{
-		cfg: cfg, req: req,
-	}
+func (permata *permataProvider) Code() string {
+	return permataBankCode
 }
 
-func NewPermataClientResponse(cfg *entity.BankConfig) *permataClientResponse {
-	return &permataClientResponse{
-		cfg: cfg,
-	}
+func (permata *permataProvider) Name() string {
+	return "PERMATA"
 }
 
-func (permata *permataClientRequest) BuildBodyRequest() []byte {
-	var wrapper map[string]any
+func (permata *permataProvider) BuildBodyRequest(cfg *entity.BankConfig, req dto.InquiryRequest) []byte {
 	headerMsg := dto.PermataInquiryHeaderRequest{
-		RequestTimeStamp: timehelper.FormatTimeToISO7(time.Now()),
-		CustReffID:       permata.req.CompanyId,
-	}
-
-	bodyMsg := dto.PermataInternalInquiryBodyRequest{
-		AccountNumber: permata.req.BeneficiaryAccount,
+		RequestTimeStamp: timehelper.FormatTimeToISO7Layout(time.Now(), timehelper.ISOLayoutWithMillisAndColonTimezone),
+		CustReffID:       req.PartnerReferenceNo,
 	}
 
-	payload := dto.PermataInternalInquiryRequest{
-		MessageHeader: headerMsg,
-		MessageBody:   bodyMsg,
+	if req.BankCode == permataBankCode {
+		payload := dto.PermataInternalInquiryWrapperRequest{
+			Request: dto.PermataInternalInquiryRequest{
+				MessageHeader: headerMsg,
+				MessageBody: dto.PermataInternalInquiryBodyRequest{
+					AccountNumber: req.BeneficiaryAccount,
+				},
+			},
+		}
+		return jsonhelper.WriteToJson(payload)
 	}
 
-	wrapper = map[string]any{
-		"AcctInqRq": payload,
+	payload := dto.PermataExternalInquiryWrapperRequest{
+		Request: dto.PermataExternalInquiryRequest{
+			MessageHeader: headerMsg,
+			MessageBody: dto.PermataExternalInquiryBodyRequest{
+				ToAccount: req.BeneficiaryAccount,
+				BankId:    req.BankCode,
+			},
+		},
 	}
-
-	return jsonhelper.WriteToJson(wrapper)
+	return jsonhelper.WriteToJson(payload)
 }
 
-func (permata *permataClientRequest) GetUrl() string {
-	return permata.cfg.InternalInquiryURL
+func (permata *permataProvider) GetUrl(cfg *entity.BankConfig, req dto.InquiryRequest) string {
+	if req.BankCode == permataBankCode {
+		return cfg.InternalInquiryURL
+	}
+	return cfg.ExternalInquiryURL
 }
 
-func (permata *permataClientRequest) GetHeaders(accessToken, externalId string, cfg *entity.BankConfig, payload []byte) map[string]string {
+// GetHeaders sign access token, timestamp and the exact body sent with the
+// static key, Permata verify it against the raw payload
+func (permata *permataProvider) GetHeaders(cfg *entity.BankConfig, req dto.InquiryRequest, accessToken, externalId string, payload []byte) map[string]string {
+	timestamp := timehelper.FormatTimeToISO7Layout(time.Now(), timehelper.ISOLayoutWithMillisAndColonTimezone)
+	stringToSign := fmt.Sprintf("%s:%s:%s", accessToken, timestamp, payload)
 	return map[string]string{
-		"Content-Type":     "application/json",
-		"OrganizationName": permata.req.CompanyId,
+		"Content-Type":      "application/json",
+		"Authorization":     "Bearer " + accessToken,
+		"OrganizationName":  cfg.OrganizationName,
+		"permata-timestamp": timestamp,
+		"permata-signature": authorization.HashHMACSHA256Base64(stringToSign, cfg.SigningKey),
 	}
 }
 
-func (permata *permataClientResponse) MapResponse(bankResponse []byte) BankResponseData {
-	if permata.cfg.BankCode == "013" {
-		var wrapper map[string]interface{}
-		json.Unmarshal(bankResponse, &wrapper)
-		data := wrapper["AcctInqRs"].(dto.PermataInternalInquiryResponse)
-		accountName := data.MessageBody.AccountName
-		responseMessage := data.MessageHeader.StatusDesc
-		return BankResponseData{
-			AccountName:     accountName,
-			ResponseMessage: responseMessage,
+func (permata *permataProvider) MapResponse(cfg *entity.BankConfig, req dto.InquiryRequest, httpStatus int, bankResponse []byte) (BankResponseData, error) {
+	if req.BankCode == permataBankCode {
+		var wrapper dto.PermataInternalInquiryWrapperResponse
+		if err := json.Unmarshal(bankResponse, &wrapper); err != nil {
+			return BankResponseData{}, err
 		}
-	} else {
-		var wrapper map[string]interface{}
-		json.Unmarshal(bankResponse, &wrapper)
-		data := wrapper["OlXferInqRs"].(dto.PermataExternalInquiryResponse)
-		accountName := data.MessageBody.ToAccountFullName
-		responseMessage := data.MessageHeader.StatusDesc
-		return BankResponseData{
-			AccountName:     accountName,
-			ResponseMessage: responseMessage,
+		if wrapper.Response == nil {
+			return permataMissingEnvelope(httpStatus, "AcctInqRs")
 		}
+		header := wrapper.Response.MessageHeader
+		return codeResponseData(permataResponseStatus, header.StatusCode, header.StatusDesc, wrapper.Response.MessageBody.AccountName), nil
+	}
+
+	var wrapper dto.PermataExternalInquiryWrapperResponse
+	if err := json.Unmarshal(bankResponse, &wrapper); err != nil {
+		return BankResponseData{}, err
+	}
+	if wrapper.Response == nil {
+		return permataMissingEnvelope(httpStatus, "OlXferInqRs")
+	}
+	header := wrapper.Response.MessageHeader
+	return codeResponseData(permataResponseStatus, header.StatusCode, header.StatusDesc, wrapper.Response.MessageBody.ToAccountFullName), nil
+}
+
+// GetAccessToken request OAuth token, Permata additionally require the API key
+// and a signature of the grant body under the static key
+func (permata *permataProvider) GetAccessToken(ctx context.Context, client *httphelper.HttpClientHelper, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+	timestamp := timehelper.FormatTimeToISO7Layout(time.Now(), timehelper.ISOLayoutWithMillisAndColonTimezone)
+	stringToSign := fmt.Sprintf("%s:%s:%s", cfg.PartnerId, timestamp, permataTokenGrantBody)
+	headers := map[string]string{
+		"API-Key":         cfg.PartnerId,
+		"OAUTH-Timestamp": timestamp,
+		"OAUTH-Signature": authorization.HashHMACSHA256Base64(stringToSign, cfg.SigningKey),
+	}
+	return authorization.GetOAuthAccessToken(ctx, client, cfg, headers, log)
+}
+
+// permataMissingEnvelope let gateway error without inquiry envelope be handled
+// by its HTTP status, the same body on HTTP 200 is a format error
+func permataMissingEnvelope(httpStatus int, envelope string) (BankResponseData, error) {
+	if httpStatus != http.StatusOK {
+		return BankResponseData{}, nil
 	}
+	return BankResponseData{}, fmt.Errorf("permata response without %s envelope", envelope)
 }
@@ -0,0 +1,77 @@
+package mapper
+
+import (
+	"briefcash-inquiry/internal/authorization"
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/jsonhelper"
+	"briefcash-inquiry/internal/helper/timehelper"
+	"encoding/json"
+	"time"
+)
+
+const (
+	snapServiceInternalInquiry = "15"
+	snapServiceExternalInquiry = "16"
+	snapTransactionSuccess     = "00"
+	snapTransactionFailed      = "06"
+)
+
+// snapStatusInquiry re-query inquiry result through SNAP transaction status API,
+// embedded by provider of bank following the SNAP standard
+type snapStatusInquiry struct{}
+
+func (snap snapStatusInquiry) BuildStatusRequest(cfg *entity.BankConfig, inquiry *entity.Inquiry) []byte {
+	serviceCode := snapServiceExternalInquiry
+	if inquiry.BeneficiaryBankCode == cfg.BankCode {
+		serviceCode = snapServiceInternalInquiry
+	}
+
+	request := dto.SNAPStatusInquiryRequest{
+		OriginalPartnerReferenceNo: inquiry.PartnerReferenceNo,
+		OriginalExternalId:         inquiry.ExternalId,
+		ServiceCode:                serviceCode,
+		TransactionDate:            timehelper.FormatTimeToISO7(inquiry.InquiryDate),
+	}
+	return jsonhelper.WriteToJson(request)
+}
+
+func (snap snapStatusInquiry) GetStatusUrl(cfg *entity.BankConfig) string {
+	return cfg.StatusInquiryURL
+}
+
+func (snap snapStatusInquiry) GetStatusHeaders(cfg *entity.BankConfig, accessToken, externalId string, payload []byte) map[string]string {
+	hexPayload := authorization.HashSHA256Hex(payload)
+	timestamp := timehelper.FormatTimeToISO7(time.Now())
+	signature := authorization.HashSignature("POST", cfg.StatusInquiryURL, accessToken, hexPayload, timestamp, cfg.ClientSecret)
+	return snapHeaders(cfg, accessToken, externalId, timestamp, signature)
+}
+
+func (snap snapStatusInquiry) MapStatusResponse(cfg *entity.BankConfig, httpStatus int, bankResponse []byte) (BankResponseData, error) {
+	var respDto dto.SNAPStatusInquiryResponse
+	if err := json.Unmarshal(bankResponse, &respDto); err != nil {
+		return BankResponseData{}, err
+	}
+
+	accountName := respDto.BeneficiaryAccountName
+	if accountName == "" {
+		accountName = respDto.AdditionalInfo["beneficiaryAccountName"]
+	}
+
+	return BankResponseData{
+		AccountName:       accountName,
+		ResponseCode:      respDto.ResponseCode,
+		ResponseMessage:   respDto.ResponseMessage,
+		TransactionStatus: respDto.LatestTransactionStatus,
+	}, nil
+}
+
+// IsTransactionSuccess tell whether SNAP latestTransactionStatus mark a successful transaction
+func IsTransactionSuccess(status string) bool {
+	return status == snapTransactionSuccess
+}
+
+// IsTransactionFailed tell whether SNAP latestTransactionStatus mark a failed transaction
+func IsTransactionFailed(status string) bool {
+	return status == snapTransactionFailed
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"briefcash-inquiry/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AsyncInquiryRepository interface {
+	CreateTask(ctx context.Context, task *entity.InquiryAsyncTask) (bool, error)
+	FindByReference(ctx context.Context, merchantCode, partnerRefNo string) (*entity.InquiryAsyncTask, error)
+	FindByTrackingId(ctx context.Context, merchantCode, trackingId string) (*entity.InquiryAsyncTask, error)
+	ClaimNextTask(ctx context.Context, staleBefore time.Time) (*entity.InquiryAsyncTask, error)
+	SaveResult(ctx context.Context, task *entity.InquiryAsyncTask) error
+	SaveDelivery(ctx context.Context, task *entity.InquiryAsyncTask, attempt *entity.InquiryCallbackLog) error
+}
+
+type asyncInquiryRepository struct {
+	db *gorm.DB
+}
+
+func NewAsyncInquiryRepository(db *gorm.DB) AsyncInquiryRepository {
+	return &asyncInquiryRepository{db}
+}
+
+// CreateTask insert new task, false is returned when the partner reference
+// is already registered by the merchant
+func (r *asyncInquiryRepository) CreateTask(ctx context.Context, task *entity.InquiryAsyncTask) (bool, error) {
+	result := r.db.WithContext(ctx).Table("inquiry_async_task").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(task)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to save async inquiry task to database: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *asyncInquiryRepository) FindByReference(ctx context.Context, merchantCode, partnerRefNo string) (*entity.InquiryAsyncTask, error) {
+	return r.findTask(ctx, "merchant_code = ? AND partner_reference_no = ?", merchantCode, partnerRefNo)
+}
+
+func (r *asyncInquiryRepository) FindByTrackingId(ctx context.Context, merchantCode, trackingId string) (*entity.InquiryAsyncTask, error) {
+	return r.findTask(ctx, "merchant_code = ? AND tracking_id = ?", merchantCode, trackingId)
+}
+
+func (r *asyncInquiryRepository) findTask(ctx context.Context, query string, args ...any) (*entity.InquiryAsyncTask, error) {
+	var task entity.InquiryAsyncTask
+
+	err := r.db.WithContext(ctx).Table("inquiry_async_task").
+		Where(query, args...).
+		First(&task).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find async inquiry task: %w", err)
+	}
+	return &task, nil
+}
+
+// ClaimNextTask take a task waiting for inquiry or callback delivery, work left
+// by a stopped worker is taken again once it is older than staleBefore
+func (r *asyncInquiryRepository) ClaimNextTask(ctx context.Context, staleBefore time.Time) (*entity.InquiryAsyncTask, error) {
+	var task *entity.InquiryAsyncTask
+
+	err := r.db.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		now := time.Now()
+
+		var candidate entity.InquiryAsyncTask
+		err := trx.Table("inquiry_async_task").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", entity.AsyncTaskQueued).
+			Or("status IN ? AND updated_at < ?", []string{entity.AsyncTaskProcessing, entity.AsyncTaskDelivering}, staleBefore).
+			Or("status = ? AND next_attempt_at <= ?", entity.AsyncTaskCallbackPending, now).
+			Order("id ASC").
+			First(&candidate).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		switch candidate.Status {
+		case entity.AsyncTaskQueued, entity.AsyncTaskProcessing:
+			candidate.Status = entity.AsyncTaskProcessing
+		default:
+			candidate.Status = entity.AsyncTaskDelivering
+		}
+		candidate.UpdatedAt = now
+
+		err = trx.Table("inquiry_async_task").
+			Where("id = ?", candidate.ID).
+			Updates(map[string]any{"status": candidate.Status, "updated_at": candidate.UpdatedAt}).Error
+		if err != nil {
+			return err
+		}
+		task = &candidate
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim async inquiry task: %w", err)
+	}
+	return task, nil
+}
+
+func (r *asyncInquiryRepository) SaveResult(ctx context.Context, task *entity.InquiryAsyncTask) error {
+	err := r.db.WithContext(ctx).Table("inquiry_async_task").
+		Where("id = ?", task.ID).
+		Updates(map[string]any{
+			"status":          task.Status,
+			"response_body":   task.ResponseBody,
+			"next_attempt_at": task.NextAttemptAt,
+			"updated_at":      task.UpdatedAt,
+		}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to save async inquiry result: %w", err)
+	}
+	return nil
+}
+
+// SaveDelivery record one callback attempt together with the task state after it
+func (r *asyncInquiryRepository) SaveDelivery(ctx context.Context, task *entity.InquiryAsyncTask, attempt *entity.InquiryCallbackLog) error {
+	err := r.db.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		if err := trx.Table("inquiry_callback_log").Create(attempt).Error; err != nil {
+			return err
+		}
+		return trx.Table("inquiry_async_task").
+			Where("id = ?", task.ID).
+			Updates(map[string]any{
+				"status":            task.Status,
+				"callback_attempts": task.CallbackAttempts,
+				"next_attempt_at":   task.NextAttemptAt,
+				"updated_at":        task.UpdatedAt,
+			}).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to save callback delivery: %w", err)
+	}
+	return nil
+}
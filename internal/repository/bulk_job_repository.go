@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"briefcash-inquiry/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BulkJobRepository interface {
+	CreateJob(ctx context.Context, job *entity.InquiryBulkJob, items []entity.InquiryBulkItem) error
+	FindJob(ctx context.Context, merchantCode, jobId string) (*entity.InquiryBulkJob, error)
+	ClaimNextJob(ctx context.Context, staleBefore time.Time) (*entity.InquiryBulkJob, error)
+	FindPendingItems(ctx context.Context, jobId string) ([]entity.InquiryBulkItem, error)
+	CompleteItem(ctx context.Context, item *entity.InquiryBulkItem) error
+	FinishJob(ctx context.Context, jobId string) (bool, error)
+	ExportItems(ctx context.Context, jobId string, handle func(*entity.InquiryBulkItem) error) error
+}
+
+type bulkJobRepository struct {
+	db *gorm.DB
+}
+
+func NewBulkJobRepository(db *gorm.DB) BulkJobRepository {
+	return &bulkJobRepository{db}
+}
+
+func (r *bulkJobRepository) CreateJob(ctx context.Context, job *entity.InquiryBulkJob, items []entity.InquiryBulkItem) error {
+	err := r.db.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		if err := trx.Table("inquiry_bulk_job").Create(job).Error; err != nil {
+			return err
+		}
+		return trx.Table("inquiry_bulk_item").CreateInBatches(items, 500).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to save bulk inquiry job to database: %w", err)
+	}
+	return nil
+}
+
+func (r *bulkJobRepository) FindJob(ctx context.Context, merchantCode, jobId string) (*entity.InquiryBulkJob, error) {
+	var job entity.InquiryBulkJob
+
+	err := r.db.WithContext(ctx).Table("inquiry_bulk_job").
+		Where("merchant_code = ? AND job_id = ?", merchantCode, jobId).
+		First(&job).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find bulk inquiry job: %w", err)
+	}
+	return &job, nil
+}
+
+// ClaimNextJob take the oldest queued job, or a processing job whose worker stopped
+// updating it, rows locked by another instance are skipped
+func (r *bulkJobRepository) ClaimNextJob(ctx context.Context, staleBefore time.Time) (*entity.InquiryBulkJob, error) {
+	var job *entity.InquiryBulkJob
+
+	err := r.db.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		var candidate entity.InquiryBulkJob
+		err := trx.Table("inquiry_bulk_job").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND updated_at < ?)", entity.BulkJobQueued, entity.BulkJobProcessing, staleBefore).
+			Order("created_at ASC").
+			First(&candidate).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		candidate.Status = entity.BulkJobProcessing
+		candidate.UpdatedAt = time.Now()
+		err = trx.Table("inquiry_bulk_job").
+			Where("id = ?", candidate.ID).
+			Updates(map[string]any{"status": candidate.Status, "updated_at": candidate.UpdatedAt}).Error
+		if err != nil {
+			return err
+		}
+		job = &candidate
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim bulk inquiry job: %w", err)
+	}
+	return job, nil
+}
+
+func (r *bulkJobRepository) FindPendingItems(ctx context.Context, jobId string) ([]entity.InquiryBulkItem, error) {
+	var items []entity.InquiryBulkItem
+
+	err := r.db.WithContext(ctx).Table("inquiry_bulk_item").
+		Where("job_id = ? AND status = ?", jobId, entity.BulkItemPending).
+		Order("row_no ASC").
+		Find(&items).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending bulk inquiry items: %w", err)
+	}
+	return items, nil
+}
+
+// CompleteItem store the item result and count it in the job progress,
+// an item already completed by another worker is not counted twice
+func (r *bulkJobRepository) CompleteItem(ctx context.Context, item *entity.InquiryBulkItem) error {
+	err := r.db.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		result := trx.Table("inquiry_bulk_item").
+			Where("id = ? AND status = ?", item.ID, entity.BulkItemPending).
+			Updates(map[string]any{
+				"status":           item.Status,
+				"response_code":    item.ResponseCode,
+				"response_message": item.ResponseMessage,
+				"beneficiary_name": item.BeneficiaryName,
+				"processed_at":     item.ProcessedAt,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		counter := "failed_rows"
+		if item.Status == entity.BulkItemSuccess {
+			counter = "success_rows"
+		}
+		return trx.Table("inquiry_bulk_job").
+			Where("job_id = ?", item.JobId).
+			Updates(map[string]any{
+				"processed_rows": gorm.Expr("processed_rows + 1"),
+				counter:          gorm.Expr(counter + " + 1"),
+				"updated_at":     time.Now(),
+			}).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update bulk inquiry item: %w", err)
+	}
+	return nil
+}
+
+// FinishJob mark the job completed only when none of its items is still pending,
+// otherwise the job is left for stale reclaim and false is returned
+func (r *bulkJobRepository) FinishJob(ctx context.Context, jobId string) (bool, error) {
+	now := time.Now()
+	pending := r.db.Table("inquiry_bulk_item").
+		Select("1").
+		Where("job_id = ? AND status = ?", jobId, entity.BulkItemPending)
+
+	result := r.db.WithContext(ctx).Table("inquiry_bulk_job").
+		Where("job_id = ?", jobId).
+		Where("NOT EXISTS (?)", pending).
+		Updates(map[string]any{
+			"status":       entity.BulkJobCompleted,
+			"updated_at":   now,
+			"completed_at": now,
+		})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update bulk inquiry job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *bulkJobRepository) ExportItems(ctx context.Context, jobId string, handle func(*entity.InquiryBulkItem) error) error {
+	db := r.db.WithContext(ctx).Table("inquiry_bulk_item").
+		Where("job_id = ?", jobId).
+		Order("row_no ASC")
+
+	rows, err := db.Rows()
+	if err != nil {
+		return fmt.Errorf("failed to export bulk inquiry items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.InquiryBulkItem
+		if err := db.ScanRows(rows, &item); err != nil {
+			return fmt.Errorf("failed to read bulk inquiry item: %w", err)
+		}
+		if err := handle(&item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type IdempotencyRedisRepository interface {
+	SetIfAbsent(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (string, bool, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+type idempotencyRedisRepository struct {
+	client *redis.Client
+}
+
+func NewIdempotencyRedisRepository(client *redis.Client) IdempotencyRedisRepository {
+	return &idempotencyRedisRepository{client}
+}
+
+func (r *idempotencyRedisRepository) SetIfAbsent(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	stored, err := r.client.SetNX(ctx, key, value, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to set idempotency key: %w", err)
+	}
+	return stored, nil
+}
+
+func (r *idempotencyRedisRepository) Get(ctx context.Context, key string) (string, bool, error) {
+	value, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return value, true, nil
+}
+
+func (r *idempotencyRedisRepository) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	if err := r.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRedisRepository) Delete(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"briefcash-inquiry/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	SaveRecord(ctx context.Context, record *entity.InquiryIdempotency) error
+	FindByReference(ctx context.Context, merchantCode, partnerRefNo string) (*entity.InquiryIdempotency, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db}
+}
+
+func (r *idempotencyRepository) SaveRecord(ctx context.Context, record *entity.InquiryIdempotency) error {
+	// first stored response win, a duplicate insert keep the original one
+	err := r.db.WithContext(ctx).Table("inquiry_idempotency").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(record).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to save idempotency record to database: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) FindByReference(ctx context.Context, merchantCode, partnerRefNo string) (*entity.InquiryIdempotency, error) {
+	var record entity.InquiryIdempotency
+
+	err := r.db.WithContext(ctx).Table("inquiry_idempotency").
+		Where("merchant_code = ? AND partner_reference_no = ?", merchantCode, partnerRefNo).
+		First(&record).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type InquiryCacheRepository interface {
+	GetResult(ctx context.Context, key string) (string, bool, error)
+	SetResult(ctx context.Context, key, value string, ttl time.Duration) error
+}
+
+type inquiryCacheRepository struct {
+	client *redis.Client
+}
+
+func NewInquiryCacheRepository(client *redis.Client) InquiryCacheRepository {
+	return &inquiryCacheRepository{client}
+}
+
+func (r *inquiryCacheRepository) GetResult(ctx context.Context, key string) (string, bool, error) {
+	value, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get inquiry cache: %w", err)
+	}
+	return value, true, nil
+}
+
+func (r *inquiryCacheRepository) SetResult(ctx context.Context, key, value string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("invalid ttl value: %v", ttl)
+	}
+	if err := r.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set inquiry cache: %w", err)
+	}
+	return nil
+}
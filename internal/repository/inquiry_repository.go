@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	model "briefcash-inquiry/internal/entity"
@@ -9,8 +10,14 @@ import (
 	"gorm.io/gorm"
 )
 
+var ErrInquiryStatusChanged = errors.New("inquiry status already changed")
+
 type InquiryRepository interface {
 	SaveInquiry(ctx context.Context, inquiry *model.Inquiry) error
+	FindByReference(ctx context.Context, merchantCode, partnerRefNo string) (*model.Inquiry, error)
+	UpdateStatus(ctx context.Context, inquiry *model.Inquiry, fromStatus string) error
+	SearchInquiries(ctx context.Context, filter model.InquiryFilter) ([]model.Inquiry, error)
+	ExportInquiries(ctx context.Context, filter model.InquiryFilter, handle func(*model.Inquiry) error) error
 	WithTransaction(trx *gorm.DB) InquiryRepository
 }
 
@@ -30,6 +37,114 @@ func (ir *inquiryRepository) SaveInquiry(ctx context.Context, inquiry *model.Inq
 	return nil
 }
 
+func (ir *inquiryRepository) FindByReference(ctx context.Context, merchantCode, partnerRefNo string) (*model.Inquiry, error) {
+	var inquiry model.Inquiry
+
+	err := ir.db.WithContext(ctx).Table("inquiry").
+		Where("merchant_code = ? AND partner_reference_no = ?", merchantCode, partnerRefNo).
+		Order("inquiry_date DESC").
+		First(&inquiry).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find inquiry data: %w", err)
+	}
+	return &inquiry, nil
+}
+
+// UpdateStatus store the new status and result of inquiry, the update only apply
+// when the stored status is still fromStatus so concurrent update can not go backwards
+func (ir *inquiryRepository) UpdateStatus(ctx context.Context, inquiry *model.Inquiry, fromStatus string) error {
+	result := ir.db.WithContext(ctx).Table("inquiry").
+		Where("id = ? AND status = ?", inquiry.ID, fromStatus).
+		Updates(map[string]any{
+			"status":                   inquiry.Status,
+			"beneficiary_account_name": inquiry.BeneficiaryAccountName,
+			"partner_bank_code":        inquiry.PartnerBankCode,
+			"external_id":              inquiry.ExternalId,
+			"cached":                   inquiry.Cached,
+			"error_code":               inquiry.ErrorCode,
+			"bank_response_code":       inquiry.BankResponseCode,
+			"latency_ms":               inquiry.LatencyMs,
+			"updated_at":               inquiry.UpdatedAt,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update inquiry data: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrInquiryStatusChanged
+	}
+	return nil
+}
+
+// SearchInquiries return one page of inquiries, newest first, using keyset on id
+func (ir *inquiryRepository) SearchInquiries(ctx context.Context, filter model.InquiryFilter) ([]model.Inquiry, error) {
+	var inquiries []model.Inquiry
+
+	query := ir.filterInquiries(ctx, filter)
+	if filter.CursorID > 0 {
+		query = query.Where("id < ?", filter.CursorID)
+	}
+
+	err := query.Order("id DESC").Limit(filter.Limit).Find(&inquiries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search inquiry data: %w", err)
+	}
+	return inquiries, nil
+}
+
+// ExportInquiries stream every matching inquiry row by row, so large export
+// does not need to be held in memory
+func (ir *inquiryRepository) ExportInquiries(ctx context.Context, filter model.InquiryFilter, handle func(*model.Inquiry) error) error {
+	db := ir.filterInquiries(ctx, filter).Order("id DESC")
+	rows, err := db.Rows()
+	if err != nil {
+		return fmt.Errorf("failed to export inquiry data: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var inquiry model.Inquiry
+		if err := db.ScanRows(rows, &inquiry); err != nil {
+			return fmt.Errorf("failed to read inquiry data: %w", err)
+		}
+		if err := handle(&inquiry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (ir *inquiryRepository) filterInquiries(ctx context.Context, filter model.InquiryFilter) *gorm.DB {
+	query := ir.db.WithContext(ctx).Table("inquiry")
+
+	if filter.MerchantCode != "" {
+		query = query.Where("merchant_code = ?", filter.MerchantCode)
+	}
+	if !filter.DateFrom.IsZero() {
+		query = query.Where("inquiry_date >= ?", filter.DateFrom)
+	}
+	if !filter.DateTo.IsZero() {
+		query = query.Where("inquiry_date < ?", filter.DateTo)
+	}
+	if filter.BankCode != "" {
+		query = query.Where("beneficiary_bank_code = ?", filter.BankCode)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.BeneficiaryAccount != "" {
+		query = query.Where("beneficiary_account = ?", filter.BeneficiaryAccount)
+	}
+	if filter.PartnerReferenceNo != "" {
+		query = query.Where("partner_reference_no = ?", filter.PartnerReferenceNo)
+	}
+	return query
+}
+
 func (ir *inquiryRepository) WithTransaction(trx *gorm.DB) InquiryRepository {
 	return &inquiryRepository{db: trx}
 }
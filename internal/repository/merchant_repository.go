@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"briefcash-inquiry/internal/entity"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type MerchantRepository interface {
+	FindAll(ctx context.Context) ([]entity.MerchantConfig, error)
+}
+
+type merchantRepository struct {
+	db *gorm.DB
+}
+
+func NewMerchantRepository(db *gorm.DB) MerchantRepository {
+	return &merchantRepository{db}
+}
+
+func (r *merchantRepository) FindAll(ctx context.Context) ([]entity.MerchantConfig, error) {
+	var listConfig []entity.MerchantConfig
+
+	err := r.db.WithContext(ctx).Table("merchant_settings").
+		Select("merchant_code, disable_inquiry_cache, callback_url, callback_secret, client_key, client_secret, public_key").
+		Scan(&listConfig).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return listConfig, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type MerchantTokenRedisRepository interface {
+	SaveToken(ctx context.Context, key, merchantCode string, ttl time.Duration) error
+	FindMerchantCode(ctx context.Context, key string) (string, bool, error)
+}
+
+type merchantTokenRedisRepository struct {
+	client *redis.Client
+}
+
+func NewMerchantTokenRedisRepository(client *redis.Client) MerchantTokenRedisRepository {
+	return &merchantTokenRedisRepository{client}
+}
+
+func (r *merchantTokenRedisRepository) SaveToken(ctx context.Context, key, merchantCode string, ttl time.Duration) error {
+	if err := r.client.Set(ctx, key, merchantCode, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save merchant access token: %w", err)
+	}
+	return nil
+}
+
+func (r *merchantTokenRedisRepository) FindMerchantCode(ctx context.Context, key string) (string, bool, error) {
+	merchantCode, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get merchant access token: %w", err)
+	}
+	return merchantCode, true, nil
+}
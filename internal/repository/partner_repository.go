@@ -24,7 +24,7 @@ func (r *partnerRepository) FindAll(ctx context.Context) ([]entity.BankConfig, e
 	var listConfig []entity.BankConfig
 
 	err := r.db.WithContext(ctx).Table("partner").
-		Select("partner.company_bank_code AS bank_code, domestic_bank.short_name AS bank_name, partner_settings.api_key AS client_key, partner_settings.api_secret AS client_secret, partner_settings.partner_id, partner_settings.channel_id, partner_url.internal_inquiry_url, partner_url.external_inquiry_url, partner_url.access_token_url, partner_url.base_url").
+		Select("partner.company_bank_code AS bank_code, domestic_bank.short_name AS bank_name, partner_settings.api_key AS client_key, partner_settings.api_secret AS client_secret, partner_settings.partner_id, partner_settings.channel_id, partner_settings.http_timeout_ms, partner_settings.max_conns_per_host, partner_settings.retry_max_attempts, partner_settings.retry_base_delay_ms, partner_settings.retry_status_codes, partner_settings.retry_response_codes, partner_settings.idempotent_external_id, partner_settings.bulk_rate_per_second, partner_settings.source_account_no, partner_settings.internal_additional_info, partner_settings.external_additional_info, partner_settings.signing_key, partner_settings.organization_name, partner_settings.provider_type, partner_settings.auth_scheme, partner_url.internal_inquiry_url, partner_url.external_inquiry_url, partner_url.status_inquiry_url, partner_url.access_token_url, partner_url.base_url").
 		Joins("INNER JOIN partner_url ON partner.company_id = partner_url.company_id").
 		Joins("INNER JOIN domestic_bank ON partner.company_id = domestic_bank.company_id").
 		Joins("INNER JOIN partner_settings ON partner.company_id = partner_settings.company_id").
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+type RateLimitRedisRepository interface {
+	AllowRate(ctx context.Context, key string, ratePerSecond int) (RateLimitResult, error)
+	AllowQuota(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error)
+}
+
+type rateLimitRedisRepository struct {
+	client *redis.Client
+}
+
+// gcraScript implement generic cell rate algorithm, the theoretical arrival time
+// is kept per key and redis clock is used so every instance share the same time
+var gcraScript = redis.NewScript(`
+local emission = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+
+local new_tat = tat + emission
+local allow_at = new_tat - emission * burst
+if allow_at > now then
+	return {0, 0, allow_at - now, tat - now}
+end
+
+redis.call("SET", KEYS[1], new_tat, "PX", new_tat - now)
+return {1, math.floor((now - allow_at) / emission), 0, new_tat - now}
+`)
+
+// quotaScript count request in a fixed window, rejected request is not counted
+var quotaScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+if current >= limit then
+	return {0, 0, redis.call("PTTL", KEYS[1])}
+end
+
+current = redis.call("INCR", KEYS[1])
+if current == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return {1, limit - current, redis.call("PTTL", KEYS[1])}
+`)
+
+func NewRateLimitRedisRepository(client *redis.Client) RateLimitRedisRepository {
+	return &rateLimitRedisRepository{client}
+}
+
+// AllowRate allow ratePerSecond request per second with a burst of one second
+func (r *rateLimitRedisRepository) AllowRate(ctx context.Context, key string, ratePerSecond int) (RateLimitResult, error) {
+	emission := 1000 / ratePerSecond
+	if emission <= 0 {
+		emission = 1
+	}
+
+	values, err := gcraScript.Run(ctx, r.client, []string{key}, emission, ratePerSecond).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+
+	return RateLimitResult{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
+
+func (r *rateLimitRedisRepository) AllowQuota(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	values, err := quotaScript.Run(ctx, r.client, []string{key}, limit, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("failed to check quota: %w", err)
+	}
+
+	resetAfter := time.Duration(values[2]) * time.Millisecond
+	result := RateLimitResult{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		ResetAfter: resetAfter,
+	}
+	if !result.Allowed {
+		result.RetryAfter = resetAfter
+	}
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"briefcash-inquiry/internal/entity"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type RateLimitRepository interface {
+	FindAll(ctx context.Context) ([]entity.MerchantRateLimit, error)
+}
+
+type rateLimitRepository struct {
+	db *gorm.DB
+}
+
+func NewRateLimitRepository(db *gorm.DB) RateLimitRepository {
+	return &rateLimitRepository{db}
+}
+
+func (r *rateLimitRepository) FindAll(ctx context.Context) ([]entity.MerchantRateLimit, error) {
+	var limits []entity.MerchantRateLimit
+
+	err := r.db.WithContext(ctx).Table("merchant_rate_limit").
+		Select("merchant_code, bank_code, per_second, per_day").
+		Scan(&limits).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return limits, nil
+}
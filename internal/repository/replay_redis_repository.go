@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type ReplayRedisRepository interface {
+	ClaimKey(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+type replayRedisRepository struct {
+	client *redis.Client
+}
+
+func NewReplayRedisRepository(client *redis.Client) ReplayRedisRepository {
+	return &replayRedisRepository{client}
+}
+
+// ClaimKey store the key only when it does not exist yet, false means the key
+// was already used
+func (r *replayRedisRepository) ClaimKey(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ok, err := r.client.SetNX(ctx, key, time.Now().Unix(), ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim replay key: %w", err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"briefcash-inquiry/internal/entity"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type RouteRepository interface {
+	FindAll(ctx context.Context) ([]entity.InquiryRoute, error)
+}
+
+type routeRepository struct {
+	db *gorm.DB
+}
+
+func NewRouteRepository(db *gorm.DB) RouteRepository {
+	return &routeRepository{db}
+}
+
+func (r *routeRepository) FindAll(ctx context.Context) ([]entity.InquiryRoute, error) {
+	var routes []entity.InquiryRoute
+
+	err := r.db.WithContext(ctx).Table("inquiry_route").
+		Select("id, merchant_code, destination_bank_code, inquiry_type, partner_bank_code, priority").
+		Order("priority ASC").
+		Scan(&routes).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return routes, nil
+}
@@ -13,12 +13,24 @@ type TokenRedisRepository interface {
 	SetToken(ctx context.Context, key, value string, ttl time.Duration) error
 	GetToken(ctx context.Context, key string) (string, error)
 	Exists(ctx context.Context, key string) (bool, error)
+	AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, key, owner string) error
+	DeleteTokenIfMatch(ctx context.Context, key, value string) error
 }
 
 type tokenRedisRepository struct {
 	client *redis.Client
 }
 
+// compareAndDeleteScript only delete the key when it still hold the expected value,
+// so a lock or token replaced by another instance is never removed by us
+var compareAndDeleteScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
 func NewTokenRedisRepository(client *redis.Client) TokenRedisRepository {
 	return &tokenRedisRepository{client}
 }
@@ -54,3 +66,25 @@ func (t *tokenRedisRepository) Exists(ctx context.Context, key string) (bool, er
 	}
 	return count > 0, nil
 }
+
+func (t *tokenRedisRepository) AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	acquired, err := t.client.SetNX(ctx, key, owner, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire redis lock: %w", err)
+	}
+	return acquired, nil
+}
+
+func (t *tokenRedisRepository) ReleaseLock(ctx context.Context, key, owner string) error {
+	if err := compareAndDeleteScript.Run(ctx, t.client, []string{key}, owner).Err(); err != nil {
+		return fmt.Errorf("failed to release redis lock: %w", err)
+	}
+	return nil
+}
+
+func (t *tokenRedisRepository) DeleteTokenIfMatch(ctx context.Context, key, value string) error {
+	if err := compareAndDeleteScript.Run(ctx, t.client, []string{key}, value).Err(); err != nil {
+		return fmt.Errorf("failed to delete redis token: %w", err)
+	}
+	return nil
+}
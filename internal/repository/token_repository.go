@@ -10,8 +10,9 @@ import (
 
 type TokenRepository interface {
 	SaveToken(ctx context.Context, token *entity.AccessToken) error
-	FindLatestValidToken(ctx context.Context) (string, error)
-	FindToken(ctx context.Context) (*entity.AccessToken, error)
+	FindLatestValidToken(ctx context.Context, bankCode, clientKey string) (string, error)
+	FindToken(ctx context.Context, bankCode, clientKey string) (*entity.AccessToken, error)
+	ExpireToken(ctx context.Context, bankCode, clientKey, accessToken string) error
 	WithTransaction(trx *gorm.DB) TokenRepository
 }
 
@@ -33,11 +34,12 @@ func (r *tokenRepository) SaveToken(ctx context.Context, token *entity.AccessTok
 	return nil
 }
 
-func (r *tokenRepository) FindLatestValidToken(ctx context.Context) (string, error) {
+func (r *tokenRepository) FindLatestValidToken(ctx context.Context, bankCode, clientKey string) (string, error) {
 	var accessToken string
 
 	err := r.db.WithContext(ctx).Table("access_token").
 		Select("access_token").
+		Where("bank_code = ? AND client_key = ?", bankCode, clientKey).
 		Where("expires_date > NOW()").Order("expires_date DESC").
 		Limit(1).Scan(&accessToken).Error
 
@@ -48,10 +50,11 @@ func (r *tokenRepository) FindLatestValidToken(ctx context.Context) (string, err
 	return accessToken, nil
 }
 
-func (r *tokenRepository) FindToken(ctx context.Context) (*entity.AccessToken, error) {
+func (r *tokenRepository) FindToken(ctx context.Context, bankCode, clientKey string) (*entity.AccessToken, error) {
 	var accessToken entity.AccessToken
 
 	err := r.db.WithContext(ctx).Table("access_token").
+		Where("bank_code = ? AND client_key = ?", bankCode, clientKey).
 		Where("expires_date > NOW()").Order("expires_date DESC").
 		Limit(1).First(&accessToken).Error
 
@@ -62,6 +65,19 @@ func (r *tokenRepository) FindToken(ctx context.Context) (*entity.AccessToken, e
 	return &accessToken, nil
 }
 
+func (r *tokenRepository) ExpireToken(ctx context.Context, bankCode, clientKey, accessToken string) error {
+	err := r.db.WithContext(ctx).Table("access_token").
+		Where("bank_code = ? AND client_key = ? AND access_token = ?", bankCode, clientKey, accessToken).
+		Where("expires_date > NOW()").
+		Update("expires_date", gorm.Expr("NOW()")).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to expire token in database %w", err)
+	}
+
+	return nil
+}
+
 func (r *tokenRepository) WithTransaction(trx *gorm.DB) TokenRepository {
 	return &tokenRepository{db: trx}
 }
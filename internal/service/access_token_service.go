@@ -1,29 +1,51 @@
 package service
 
 import (
+	"briefcash-inquiry/internal/dto"
 	"briefcash-inquiry/internal/entity"
 	"briefcash-inquiry/internal/repository"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 )
 
+// tokenLockTTL outlive tokenRefreshTimeout, so the lock never expire while its
+// holder is still fetching and no other instance request a token in between
+const (
+	tokenLockTTL        = 30 * time.Second
+	tokenRefreshTimeout = 20 * time.Second
+	tokenPollInterval   = 100 * time.Millisecond
+)
+
+// TokenFetcher request a new access token from the bank
+type TokenFetcher func(ctx context.Context, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error)
+
 type TokenService interface {
 	SaveAccessTokenDB(ctx context.Context, token *entity.AccessToken) error
-	SaveAccessTokenRedis(ctx context.Context, bank string, token *entity.AccessToken) error
-	GetActiveAccessToken(ctx context.Context, bank string) (string, error)
+	SaveAccessTokenRedis(ctx context.Context, token *entity.AccessToken) error
+	GetActiveAccessToken(ctx context.Context, bankCode, clientKey string) (string, error)
+	GetActiveTokenDetail(ctx context.Context, bankCode, clientKey string) (*entity.AccessToken, error)
+	GetOrRefreshAccessToken(ctx context.Context, cfg *entity.BankConfig, fetch TokenFetcher, log *logrus.Entry) (string, error)
+	RefreshAccessToken(ctx context.Context, cfg *entity.BankConfig, fetch TokenFetcher, log *logrus.Entry) (string, error)
+	InvalidateAccessToken(ctx context.Context, bankCode, clientKey, accessToken string) error
 }
 
 type tokenService struct {
 	db         *gorm.DB
 	tokenRepo  repository.TokenRepository
 	tokenRedis repository.TokenRedisRepository
+	group      singleflight.Group
 }
 
 func NewTokenService(db *gorm.DB, tokenRepo repository.TokenRepository, tokenRedis repository.TokenRedisRepository) TokenService {
-	return &tokenService{db, tokenRepo, tokenRedis}
+	return &tokenService{db: db, tokenRepo: tokenRepo, tokenRedis: tokenRedis}
 }
 
 func (s *tokenService) SaveAccessTokenDB(ctx context.Context, token *entity.AccessToken) error {
@@ -34,7 +56,7 @@ func (s *tokenService) SaveAccessTokenDB(ctx context.Context, token *entity.Acce
 	return nil
 }
 
-func (s *tokenService) SaveAccessTokenRedis(ctx context.Context, bank string, token *entity.AccessToken) error {
+func (s *tokenService) SaveAccessTokenRedis(ctx context.Context, token *entity.AccessToken) error {
 	// Save new access token to redis
 	expiresIn := token.ExpiresIn
 	if expiresIn <= 30 {
@@ -42,41 +64,204 @@ func (s *tokenService) SaveAccessTokenRedis(ctx context.Context, bank string, to
 	}
 	ttl := time.Duration(expiresIn-30) * time.Second
 
-	key := fmt.Sprintf("%s:access_token", bank)
+	key := tokenRedisKey(token.BankCode, token.ClientKey)
 	if err := s.tokenRedis.SetToken(ctx, key, token.AccessToken, ttl); err != nil {
 		return fmt.Errorf("failed to set new access token to redis")
 	}
 	return nil
 }
 
-func (s *tokenService) GetActiveAccessToken(ctx context.Context, bank string) (string, error) {
+func (s *tokenService) GetActiveAccessToken(ctx context.Context, bankCode, clientKey string) (string, error) {
 	// Check latest active access token in redis
-	key := fmt.Sprintf("%s:access_token", bank)
+	key := tokenRedisKey(bankCode, clientKey)
 	value, err := s.tokenRedis.GetToken(ctx, key)
 	if err == nil {
 		return value, nil
 	}
 
-	// fallback to database
-	tokenEntity, err := s.tokenRepo.FindToken(ctx)
+	// fallback to database, only token issued for the same bank and credential
+	tokenEntity, err := s.tokenRepo.FindToken(ctx, bankCode, clientKey)
 	if err != nil {
 		return "", fmt.Errorf("token not found in redis and database: %w", err)
 	}
 
-	expiresIn := tokenEntity.ExpiresIn
-	if expiresIn <= 30 {
-		expiresIn = 30
-	}
-
-	ttl := time.Duration(expiresIn-30) * time.Second
+	// cache only for the remaining lifetime of the token
+	ttl := time.Until(tokenEntity.ExpiresDate)
 	_ = s.tokenRedis.SetToken(ctx, key, tokenEntity.AccessToken, ttl)
 
 	return tokenEntity.AccessToken, nil
 }
 
+func (s *tokenService) GetActiveTokenDetail(ctx context.Context, bankCode, clientKey string) (*entity.AccessToken, error) {
+	return s.tokenRepo.FindToken(ctx, bankCode, clientKey)
+}
+
+func (s *tokenService) GetOrRefreshAccessToken(ctx context.Context, cfg *entity.BankConfig, fetch TokenFetcher, log *logrus.Entry) (string, error) {
+	log.WithField("step", "check_active_access_token").Info("Checking active access token in redis and database")
+	accessToken, err := s.GetActiveAccessToken(ctx, cfg.BankCode, cfg.ClientKey)
+	if err == nil {
+		return accessToken, nil
+	}
+
+	return s.refresh(ctx, cfg, fetch, false, log)
+}
+
+func (s *tokenService) RefreshAccessToken(ctx context.Context, cfg *entity.BankConfig, fetch TokenFetcher, log *logrus.Entry) (string, error) {
+	return s.refresh(ctx, cfg, fetch, true, log)
+}
+
+func (s *tokenService) InvalidateAccessToken(ctx context.Context, bankCode, clientKey, accessToken string) error {
+	// Evict only the rejected token, a newer token saved by another request is kept
+	key := tokenRedisKey(bankCode, clientKey)
+	if err := s.tokenRedis.DeleteTokenIfMatch(ctx, key, accessToken); err != nil {
+		return fmt.Errorf("failed to evict access token from redis: %w", err)
+	}
+
+	if err := s.tokenRepo.ExpireToken(ctx, bankCode, clientKey, accessToken); err != nil {
+		return fmt.Errorf("failed to expire access token in database: %w", err)
+	}
+	return nil
+}
+
+func (s *tokenService) refresh(ctx context.Context, cfg *entity.BankConfig, fetch TokenFetcher, force bool, log *logrus.Entry) (string, error) {
+	// Coalesce concurrent refresh in this instance, caller only wait for the shared result
+	key := tokenRedisKey(cfg.BankCode, cfg.ClientKey)
+	result := s.group.DoChan(key, func() (any, error) {
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tokenRefreshTimeout)
+		defer cancel()
+		return s.refreshAccessToken(refreshCtx, cfg, fetch, force, log)
+	})
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return "", res.Err
+		}
+		return res.Val.(string), nil
+	}
+}
+
+func (s *tokenService) refreshAccessToken(ctx context.Context, cfg *entity.BankConfig, fetch TokenFetcher, force bool, log *logrus.Entry) (string, error) {
+	key := tokenRedisKey(cfg.BankCode, cfg.ClientKey)
+	lockKey := key + ":lock"
+	owner := newLockOwner()
+
+	log.WithField("step", "acquire_token_lock").Info("Acquire distributed lock before refreshing access token")
+	acquired, err := s.tokenRedis.AcquireLock(ctx, lockKey, owner, tokenLockTTL)
+	if err != nil {
+		// redis unavailable, refresh without lock rather than failing the inquiry
+		log.WithField("step", "acquire_token_lock").WithError(err).Warn("Failed to acquire token lock, refreshing without lock")
+	} else if !acquired {
+		log.WithField("step", "acquire_token_lock").Info("Token refresh is running on another instance, waiting for the result")
+		return s.waitForToken(ctx, key)
+	} else {
+		defer func() {
+			if err := s.tokenRedis.ReleaseLock(context.WithoutCancel(ctx), lockKey, owner); err != nil {
+				log.WithField("step", "release_token_lock").WithError(err).Warn("Failed to release token lock")
+			}
+		}()
+
+		// another instance may have finished the refresh right before we hold the lock,
+		// forced refresh skip this because the cached token is the one being replaced
+		if !force {
+			if value, err := s.tokenRedis.GetToken(ctx, key); err == nil {
+				return value, nil
+			}
+		}
+	}
+
+	return s.fetchAndSaveToken(ctx, cfg, fetch, log)
+}
+
+func (s *tokenService) fetchAndSaveToken(ctx context.Context, cfg *entity.BankConfig, fetch TokenFetcher, log *logrus.Entry) (string, error) {
+	log.WithField("step", "get_new_access_token").Info("Get new access token from bank")
+	respToken, err := fetch(ctx, cfg, log)
+	if err != nil {
+		log.WithField("step", "get_new_access_token").WithError(err).Error("Failed to get new access token from bank")
+		return "", err
+	}
+
+	if respToken.AccessToken == "" {
+		return "", fmt.Errorf("missing access token after refresh")
+	}
+
+	token := &entity.AccessToken{
+		BankCode:    cfg.BankCode,
+		ClientKey:   cfg.ClientKey,
+		AccessToken: respToken.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   respToken.ExpiresIn,
+		ExpiresDate: time.Now().Add(time.Duration(respToken.ExpiresIn-30) * time.Second),
+	}
+
+	log.WithField("step", "get_new_access_token").Info("Access token retrieved, saving data to database and redis, running on goroutine")
+	errorChn := make(chan error, 2)
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		err := s.SaveAccessTokenDB(ctx, token)
+		if err != nil {
+			errorChn <- fmt.Errorf("failed to save access token to database: %v", err)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		err := s.SaveAccessTokenRedis(ctx, token)
+		if err != nil {
+			errorChn <- fmt.Errorf("failed to save access token to redis: %v", err)
+		}
+	}()
+
+	wg.Wait()
+	close(errorChn)
+
+	for er := range errorChn {
+		log.WithField("step", "get_new_access_token").WithError(er).Warn("Failed to save new access token (Redis/DB)")
+	}
+
+	log.WithField("step", "get_new_access_token").Info("Saving data is done")
+	return token.AccessToken, nil
+}
+
+// waitForToken poll redis until the lock holder publish the fresh token
+func (s *tokenService) waitForToken(ctx context.Context, key string) (string, error) {
+	ticker := time.NewTicker(tokenPollInterval)
+	defer ticker.Stop()
+
+	deadline := time.After(tokenLockTTL)
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-deadline:
+			return "", fmt.Errorf("timeout waiting for access token refresh")
+		case <-ticker.C:
+			if value, err := s.tokenRedis.GetToken(ctx, key); err == nil {
+				return value, nil
+			}
+		}
+	}
+}
+
 func (s *tokenService) saveToken(ctx context.Context, token *entity.AccessToken) error {
 	return s.db.Transaction(func(tx *gorm.DB) error {
 		repo := s.tokenRepo.WithTransaction(tx)
 		return repo.SaveToken(ctx, token)
 	})
 }
+
+// tokenRedisKey isolate access token per bank and partner credential
+func tokenRedisKey(bankCode, clientKey string) string {
+	return fmt.Sprintf("access_token:%s:%s", bankCode, clientKey)
+}
+
+func newLockOwner() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
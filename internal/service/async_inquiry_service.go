@@ -0,0 +1,369 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/authorization"
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/httphelper"
+	"briefcash-inquiry/internal/helper/jsonhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/helper/retryhelper"
+	"briefcash-inquiry/internal/helper/timehelper"
+	"briefcash-inquiry/internal/repository"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const callbackResponseLimit = 1024
+
+var (
+	ErrCallbackNotRegistered = errors.New("merchant callback url is not registered")
+	ErrAsyncTaskNotFound     = errors.New("async inquiry task not found")
+)
+
+type AsyncInquiryConfig struct {
+	Workers         int
+	PollInterval    time.Duration
+	StaleTimeout    time.Duration
+	CallbackTimeout time.Duration
+	CallbackRetry   retryhelper.RetryPolicy
+}
+
+type AsyncInquiryService interface {
+	Submit(ctx context.Context, req dto.InquiryRequest, partnerRefNo string) (*dto.AsyncInquiryResponse, error)
+	GetTask(ctx context.Context, merchantCode, trackingId string) (*dto.AsyncInquiryResponse, error)
+	Start(ctx context.Context)
+}
+
+type asyncInquiryService struct {
+	repo        repository.AsyncInquiryRepository
+	inquirySvc  InquiryService
+	idempotency IdempotencyService
+	merchants   MerchantService
+	rateLimits  RateLimitService
+	client      *httphelper.HttpClientHelper
+	cfg         AsyncInquiryConfig
+	wake        chan struct{}
+}
+
+func NewAsyncInquiryService(repo repository.AsyncInquiryRepository, inquirySvc InquiryService, idempotency IdempotencyService, merchants MerchantService, rateLimits RateLimitService, clients *httphelper.ClientRegistry, cfg AsyncInquiryConfig) AsyncInquiryService {
+	clientCfg := httphelper.DefaultClientConfig
+	clientCfg.Timeout = cfg.CallbackTimeout
+
+	return &asyncInquiryService{
+		repo:        repo,
+		inquirySvc:  inquirySvc,
+		idempotency: idempotency,
+		merchants:   merchants,
+		rateLimits:  rateLimits,
+		client:      clients.Get("merchant_callback", clientCfg),
+		cfg:         cfg,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Submit register the inquiry to be processed in background, a repeated partner
+// reference with the same payload return the task created before
+func (s *asyncInquiryService) Submit(ctx context.Context, req dto.InquiryRequest, partnerRefNo string) (*dto.AsyncInquiryResponse, error) {
+	merchant, ok := s.merchants.GetMerchantConfig(req.CompanyId)
+	if !ok || merchant.CallbackURL == "" {
+		return nil, ErrCallbackNotRegistered
+	}
+
+	now := time.Now()
+	task := &entity.InquiryAsyncTask{
+		TrackingId:         newTrackingId(now),
+		MerchantCode:       req.CompanyId,
+		PartnerReferenceNo: partnerRefNo,
+		RequestHash:        hashInquiryRequest(req),
+		RequestBody:        string(jsonhelper.WriteToJson(req)),
+		Status:             entity.AsyncTaskQueued,
+		NextAttemptAt:      now,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	created, err := s.repo.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		existing, err := s.repo.FindByReference(ctx, req.CompanyId, partnerRefNo)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil || existing.RequestHash != task.RequestHash {
+			return nil, ErrReferenceConflict
+		}
+		return buildAsyncResponse(existing, "Inquiry already accepted"), nil
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return buildAsyncResponse(task, "Inquiry accepted"), nil
+}
+
+func (s *asyncInquiryService) GetTask(ctx context.Context, merchantCode, trackingId string) (*dto.AsyncInquiryResponse, error) {
+	task, err := s.repo.FindByTrackingId(ctx, merchantCode, trackingId)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrAsyncTaskNotFound
+	}
+
+	response := buildAsyncResponse(task, "Inquiry task found")
+	if task.ResponseBody != "" {
+		var result dto.InquiryResponse
+		if err := json.Unmarshal([]byte(task.ResponseBody), &result); err == nil {
+			response.Data.Result = &result
+		}
+	}
+	return response, nil
+}
+
+func (s *asyncInquiryService) Start(ctx context.Context) {
+	loghelper.Logger.WithFields(logrus.Fields{
+		"service": "async_inquiry_service",
+		"workers": s.cfg.Workers,
+	}).Info("Async inquiry workers started")
+
+	for i := 0; i < s.cfg.Workers; i++ {
+		go s.runWorker(ctx)
+	}
+}
+
+func (s *asyncInquiryService) runWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && s.processNextTask(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// processNextTask claim and handle one task, false means nothing left to do for now
+func (s *asyncInquiryService) processNextTask(ctx context.Context) bool {
+	task, err := s.repo.ClaimNextTask(ctx, time.Now().Add(-s.cfg.StaleTimeout))
+	if err != nil {
+		loghelper.Logger.WithField("service", "async_inquiry_service").WithError(err).Error("Failed to claim async inquiry task")
+		return false
+	}
+	if task == nil {
+		return false
+	}
+
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":     "async_inquiry_service",
+		"tracking_id": task.TrackingId,
+		"merchant":    task.MerchantCode,
+		"partner_ref": task.PartnerReferenceNo,
+	})
+
+	if task.Status == entity.AsyncTaskProcessing {
+		if !s.runInquiry(ctx, task, log) {
+			return true
+		}
+	}
+	s.deliverCallback(ctx, task, log)
+	return true
+}
+
+func (s *asyncInquiryService) runInquiry(ctx context.Context, task *entity.InquiryAsyncTask, log *logrus.Entry) bool {
+	log = log.WithField("operation", "run_inquiry")
+
+	var req dto.InquiryRequest
+	if err := json.Unmarshal([]byte(task.RequestBody), &req); err != nil {
+		log.WithField("step", "read_request").WithError(err).Error("Invalid stored async inquiry request")
+		response, _ := errorhelper.BuildErrorResponse(errorhelper.ErrorDetail{
+			Code:       "INTERNAL_SERVER_ERROR",
+			Message:    "Internal server error occured",
+			LogMessage: err.Error(),
+			Source:     errorhelper.SourceInternal,
+		}, "", err)
+		return s.saveResult(ctx, task, response, log)
+	}
+
+	// the task count against the merchant limits when it run, not when it is submitted
+	if err := s.rateLimits.Wait(ctx, task.MerchantCode, req.BankCode, req.Type); err != nil {
+		if !errors.Is(err, ErrRateLimited) {
+			return false
+		}
+		log.WithField("step", "check_rate_limit").Warn("Async inquiry rejected by merchant rate limit")
+		response, _ := errorhelper.BuildErrorResponse(errorhelper.ClientRateLimited, "", err)
+		return s.saveResult(ctx, task, response, log)
+	}
+
+	// the partner reference is shared with the synchronous endpoint, so the
+	// inquiry run once whichever of them receive the reference first
+	log.WithField("step", "send_inquiry_request").Info("Running async inquiry")
+	_, response, err := s.idempotency.Execute(ctx, task.MerchantCode, task.PartnerReferenceNo, req, func(ctx context.Context) (int, *dto.InquiryResponse) {
+		response, err := s.inquirySvc.InquiryAccount(ctx, req, task.PartnerReferenceNo)
+		if err != nil {
+			return errorhelper.InquiryHttpStatus(response.Code), response
+		}
+		return http.StatusOK, response
+	})
+	if ctx.Err() != nil {
+		// shutting down, the task is picked up again once stale
+		return false
+	}
+
+	if errors.Is(err, ErrReferenceConflict) {
+		log.WithField("step", "check_partner_reference").WithError(err).Warn("Partner reference already used with different payload")
+		response, _ = errorhelper.BuildErrorResponse(errorhelper.ErrorDetail{
+			Code:       "CLIENT_REFERENCE_CONFLICT",
+			Message:    "X-PARTNER-REFERENCE already used with different payload",
+			LogMessage: "Partner reference conflict",
+			Source:     errorhelper.SourceClient,
+		}, "", err)
+		return s.saveResult(ctx, task, response, log)
+	}
+	if err != nil {
+		// reference still in progress or idempotency store unavailable, the task is picked up again once stale
+		log.WithField("step", "check_partner_reference").WithError(err).Warn("Failed to process partner reference, retry later")
+		return false
+	}
+	return s.saveResult(ctx, task, response, log)
+}
+
+func (s *asyncInquiryService) saveResult(ctx context.Context, task *entity.InquiryAsyncTask, response *dto.InquiryResponse, log *logrus.Entry) bool {
+	now := time.Now()
+	task.Status = entity.AsyncTaskDelivering
+	task.ResponseBody = string(jsonhelper.WriteToJson(response))
+	task.NextAttemptAt = now
+	task.UpdatedAt = now
+
+	if err := s.repo.SaveResult(context.WithoutCancel(ctx), task); err != nil {
+		log.WithField("step", "save_result").WithError(err).Error("Failed to save async inquiry result")
+		return false
+	}
+	return true
+}
+
+func (s *asyncInquiryService) deliverCallback(ctx context.Context, task *entity.InquiryAsyncTask, log *logrus.Entry) {
+	log = log.WithField("operation", "deliver_callback")
+	attempt := &entity.InquiryCallbackLog{
+		TrackingId: task.TrackingId,
+		Attempt:    task.CallbackAttempts + 1,
+		CreatedAt:  time.Now(),
+	}
+
+	merchant, ok := s.merchants.GetMerchantConfig(task.MerchantCode)
+	if !ok || merchant.CallbackURL == "" {
+		attempt.ErrorMessage = ErrCallbackNotRegistered.Error()
+	} else {
+		attempt.CallbackURL = merchant.CallbackURL
+		s.sendCallback(ctx, task, merchant, attempt)
+	}
+
+	task.CallbackAttempts = attempt.Attempt
+	task.UpdatedAt = time.Now()
+	switch {
+	case attempt.HttpStatus >= 200 && attempt.HttpStatus < 300:
+		task.Status = entity.AsyncTaskDelivered
+		log.WithField("step", "deliver_callback").Info("Callback delivered to merchant")
+	case attempt.CallbackURL == "" || task.CallbackAttempts >= s.cfg.CallbackRetry.MaxAttempts:
+		task.Status = entity.AsyncTaskFailed
+		log.WithField("step", "deliver_callback").Errorf("Giving up callback delivery after %d attempts: %s", task.CallbackAttempts, attempt.ErrorMessage)
+	default:
+		delay := s.cfg.CallbackRetry.Backoff(task.CallbackAttempts)
+		task.Status = entity.AsyncTaskCallbackPending
+		task.NextAttemptAt = time.Now().Add(delay)
+		log.WithFields(logrus.Fields{
+			"step":        "deliver_callback",
+			"http_status": attempt.HttpStatus,
+			"delay":       delay.String(),
+		}).Warnf("Callback delivery failed, retrying later: %s", attempt.ErrorMessage)
+	}
+
+	if err := s.repo.SaveDelivery(context.WithoutCancel(ctx), task, attempt); err != nil {
+		log.WithField("step", "save_delivery").WithError(err).Error("Failed to save callback delivery")
+	}
+}
+
+func (s *asyncInquiryService) sendCallback(ctx context.Context, task *entity.InquiryAsyncTask, merchant entity.MerchantConfig, attempt *entity.InquiryCallbackLog) {
+	var response dto.InquiryResponse
+	if err := json.Unmarshal([]byte(task.ResponseBody), &response); err != nil {
+		attempt.ErrorMessage = fmt.Sprintf("invalid stored inquiry response: %v", err)
+		return
+	}
+
+	payload := jsonhelper.WriteToJson(dto.InquiryCallbackPayload{
+		TrackingId:         task.TrackingId,
+		PartnerReferenceNo: task.PartnerReferenceNo,
+		InquiryResponse:    response,
+	})
+
+	relativeUrl := merchant.CallbackURL
+	if parsed, err := url.Parse(merchant.CallbackURL); err == nil {
+		relativeUrl = parsed.RequestURI()
+	}
+	timestamp := timehelper.FormatTimeToISO7(time.Now())
+	signature := authorization.HashCallbackSignature("POST", relativeUrl, authorization.HashSHA256Hex(payload), timestamp, merchant.CallbackSecret)
+
+	headers := map[string]string{
+		"Content-Type":       "application/json",
+		"X-TIMESTAMP":        timestamp,
+		"X-SIGNATURE":        signature,
+		"X-TRACKING-ID":      task.TrackingId,
+		"X-CALLBACK-ATTEMPT": strconv.Itoa(attempt.Attempt),
+	}
+
+	start := time.Now()
+	body, httpStatus, err := s.client.SendRequest(ctx, "POST", merchant.CallbackURL, payload, headers)
+	attempt.LatencyMs = time.Since(start).Milliseconds()
+	attempt.HttpStatus = httpStatus
+	if len(body) > callbackResponseLimit {
+		body = body[:callbackResponseLimit]
+	}
+	attempt.ResponseBody = string(body)
+	if err != nil {
+		attempt.ErrorMessage = err.Error()
+	} else if httpStatus < 200 || httpStatus >= 300 {
+		attempt.ErrorMessage = fmt.Sprintf("merchant returned status: %d", httpStatus)
+	}
+}
+
+func buildAsyncResponse(task *entity.InquiryAsyncTask, message string) *dto.AsyncInquiryResponse {
+	return &dto.AsyncInquiryResponse{
+		Status:  true,
+		Code:    "SUCCESS",
+		Message: message,
+		Source:  errorhelper.SourceInternal,
+		Data: dto.AsyncInquiryData{
+			TrackingId:         task.TrackingId,
+			PartnerReferenceNo: task.PartnerReferenceNo,
+			TaskStatus:         task.Status,
+			CallbackAttempts:   task.CallbackAttempts,
+		},
+	}
+}
+
+func newTrackingId(now time.Time) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("INQ%s%s", now.Format("20060102150405"), hex.EncodeToString(suffix))
+}
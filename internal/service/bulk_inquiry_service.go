@@ -0,0 +1,365 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/helper/ratehelper"
+	"briefcash-inquiry/internal/helper/retryhelper"
+	"briefcash-inquiry/internal/helper/timehelper"
+	"briefcash-inquiry/internal/repository"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidBulkRequest = errors.New("invalid bulk inquiry request")
+	ErrBulkJobNotFound    = errors.New("bulk inquiry job not found")
+)
+
+type BulkInquiryConfig struct {
+	Workers       int
+	RatePerSecond int // default rate per partner bank when not set in bank config
+	MaxRows       int
+	PollInterval  time.Duration
+	StaleTimeout  time.Duration
+}
+
+type BulkInquiryService interface {
+	CreateJob(ctx context.Context, merchantCode string, rows []dto.BulkInquiryRow) (*dto.BulkJobResponse, error)
+	GetJob(ctx context.Context, merchantCode, jobId string) (*dto.BulkJobResponse, error)
+	ExportResults(ctx context.Context, merchantCode, jobId string, handle func(dto.BulkInquiryResult) error) error
+	Start(ctx context.Context)
+}
+
+type bulkInquiryService struct {
+	repo       repository.BulkJobRepository
+	inquirySvc InquiryService
+	routeSvc   RouteService
+	bankRepo   BankPartner
+	rateRedis  repository.RateLimitRedisRepository
+	rateLimits RateLimitService
+	limiters   *ratehelper.LimiterRegistry
+	cfg        BulkInquiryConfig
+	wake       chan struct{}
+}
+
+func NewBulkInquiryService(repo repository.BulkJobRepository, inquirySvc InquiryService, routeSvc RouteService, bankRepo BankPartner, rateRedis repository.RateLimitRedisRepository, rateLimits RateLimitService, cfg BulkInquiryConfig) BulkInquiryService {
+	// without worker no item is ever received and processJob would block forever
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+
+	return &bulkInquiryService{
+		repo:       repo,
+		inquirySvc: inquirySvc,
+		routeSvc:   routeSvc,
+		bankRepo:   bankRepo,
+		rateRedis:  rateRedis,
+		rateLimits: rateLimits,
+		limiters:   ratehelper.NewLimiterRegistry(),
+		cfg:        cfg,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+func (s *bulkInquiryService) CreateJob(ctx context.Context, merchantCode string, rows []dto.BulkInquiryRow) (*dto.BulkJobResponse, error) {
+	if merchantCode == "" {
+		return nil, fmt.Errorf("%w: company_id is required", ErrInvalidBulkRequest)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no inquiry rows", ErrInvalidBulkRequest)
+	}
+	if len(rows) > s.cfg.MaxRows {
+		return nil, fmt.Errorf("%w: maximum %d rows per job", ErrInvalidBulkRequest, s.cfg.MaxRows)
+	}
+
+	now := time.Now()
+	job := &entity.InquiryBulkJob{
+		JobId:        newBulkJobId(now),
+		MerchantCode: merchantCode,
+		Status:       entity.BulkJobQueued,
+		TotalRows:    len(rows),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	items := make([]entity.InquiryBulkItem, 0, len(rows))
+	for i, row := range rows {
+		rowNo := i + 1
+		if row.BeneficiaryAccount == "" || row.BankCode == "" {
+			return nil, fmt.Errorf("%w: row %d missing account or bank code", ErrInvalidBulkRequest, rowNo)
+		}
+
+		partnerRefNo := row.PartnerReferenceNo
+		if partnerRefNo == "" {
+			partnerRefNo = fmt.Sprintf("%s-%d", job.JobId, rowNo)
+		}
+		items = append(items, entity.InquiryBulkItem{
+			JobId:              job.JobId,
+			RowNo:              rowNo,
+			PartnerReferenceNo: partnerRefNo,
+			BeneficiaryAccount: row.BeneficiaryAccount,
+			BankCode:           row.BankCode,
+			InquiryType:        row.Type,
+			Status:             entity.BulkItemPending,
+		})
+	}
+
+	if err := s.repo.CreateJob(ctx, job, items); err != nil {
+		return nil, err
+	}
+
+	// wake the dispatcher instead of waiting for the next poll
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return buildBulkJobResponse(job, "Bulk inquiry job created"), nil
+}
+
+func (s *bulkInquiryService) GetJob(ctx context.Context, merchantCode, jobId string) (*dto.BulkJobResponse, error) {
+	job, err := s.repo.FindJob(ctx, merchantCode, jobId)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrBulkJobNotFound
+	}
+	return buildBulkJobResponse(job, "Bulk inquiry job found"), nil
+}
+
+func (s *bulkInquiryService) ExportResults(ctx context.Context, merchantCode, jobId string, handle func(dto.BulkInquiryResult) error) error {
+	job, err := s.repo.FindJob(ctx, merchantCode, jobId)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return ErrBulkJobNotFound
+	}
+
+	return s.repo.ExportItems(ctx, jobId, func(item *entity.InquiryBulkItem) error {
+		return handle(dto.BulkInquiryResult{
+			RowNo:              item.RowNo,
+			PartnerReferenceNo: item.PartnerReferenceNo,
+			BeneficiaryAccount: item.BeneficiaryAccount,
+			BankCode:           item.BankCode,
+			Type:               item.InquiryType,
+			ItemStatus:         item.Status,
+			ResponseCode:       item.ResponseCode,
+			ResponseMessage:    item.ResponseMessage,
+			BeneficiaryName:    item.BeneficiaryName,
+		})
+	})
+}
+
+// Start run the job dispatcher, jobs are claimed from database one at a time
+// so several instances can share the queue, the per bank rate is shared too
+func (s *bulkInquiryService) Start(ctx context.Context) {
+	loghelper.Logger.WithFields(logrus.Fields{
+		"service": "bulk_inquiry_service",
+		"workers": s.cfg.Workers,
+	}).Info("Bulk inquiry dispatcher started")
+
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			s.processQueuedJobs(ctx)
+
+			select {
+			case <-ctx.Done():
+				loghelper.Logger.WithField("service", "bulk_inquiry_service").Info("Bulk inquiry dispatcher stopped")
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+func (s *bulkInquiryService) processQueuedJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := s.repo.ClaimNextJob(ctx, time.Now().Add(-s.cfg.StaleTimeout))
+		if err != nil {
+			loghelper.Logger.WithField("service", "bulk_inquiry_service").WithError(err).Error("Failed to claim bulk inquiry job")
+			return
+		}
+		if job == nil {
+			return
+		}
+		s.processJob(ctx, job)
+	}
+}
+
+func (s *bulkInquiryService) processJob(ctx context.Context, job *entity.InquiryBulkJob) {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "bulk_inquiry_service",
+		"operation": "process_job",
+		"job_id":    job.JobId,
+		"merchant":  job.MerchantCode,
+	})
+
+	items, err := s.repo.FindPendingItems(ctx, job.JobId)
+	if err != nil {
+		log.WithField("step", "load_items").WithError(err).Error("Failed to load bulk inquiry items")
+		return
+	}
+	log.WithField("step", "load_items").Infof("Processing %d pending bulk inquiry items", len(items))
+
+	queue := make(chan entity.InquiryBulkItem)
+	var wg sync.WaitGroup
+	for i := 0; i < s.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				s.processItem(ctx, job.MerchantCode, item, log)
+			}
+		}()
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- item
+	}
+	close(queue)
+	wg.Wait()
+
+	// unfinished job is picked up again once it is considered stale
+	if ctx.Err() != nil {
+		log.WithField("step", "finish_job").Warn("Bulk inquiry job interrupted before all items processed")
+		return
+	}
+
+	finished, err := s.repo.FinishJob(context.WithoutCancel(ctx), job.JobId)
+	if err != nil {
+		log.WithField("step", "finish_job").WithError(err).Error("Failed to mark bulk inquiry job completed")
+		return
+	}
+	if !finished {
+		log.WithField("step", "finish_job").Warn("Bulk inquiry job still has pending items, left for stale reclaim")
+		return
+	}
+	log.WithField("step", "finish_job").Info("Bulk inquiry job completed")
+}
+
+func (s *bulkInquiryService) processItem(ctx context.Context, merchantCode string, item entity.InquiryBulkItem, log *logrus.Entry) {
+	req := dto.InquiryRequest{
+		CompanyId:          merchantCode,
+		BeneficiaryAccount: item.BeneficiaryAccount,
+		PartnerReferenceNo: item.PartnerReferenceNo,
+		BankCode:           item.BankCode,
+		Type:               item.InquiryType,
+	}
+
+	// every row count against the merchant limits, not only the submit request
+	var response *dto.InquiryResponse
+	err := s.rateLimits.Wait(ctx, merchantCode, req.BankCode, req.Type)
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		response, _ = errorhelper.BuildErrorResponse(errorhelper.ClientRateLimited, "", err)
+	case err != nil:
+		return
+	default:
+		if err := s.waitBankRate(ctx, req, log); err != nil {
+			return
+		}
+		response, _ = s.inquirySvc.InquiryAccount(ctx, req, item.PartnerReferenceNo)
+	}
+	if ctx.Err() != nil {
+		// leave the item pending so it is retried when the job resume
+		return
+	}
+
+	processedAt := time.Now()
+	item.Status = entity.BulkItemFailed
+	if response.Status {
+		item.Status = entity.BulkItemSuccess
+	}
+	item.ResponseCode = response.Code
+	item.ResponseMessage = response.Message
+	item.BeneficiaryName = response.Data.BeneficiaryName
+	item.ProcessedAt = &processedAt
+
+	if err := s.repo.CompleteItem(context.WithoutCancel(ctx), &item); err != nil {
+		log.WithFields(logrus.Fields{
+			"step":   "save_item_result",
+			"row_no": item.RowNo,
+		}).WithError(err).Error("Failed to save bulk inquiry item result")
+	}
+}
+
+// waitBankRate block until the bulk rate of the first partner bank routed for
+// the request allow one more call, so bulk traffic to one bank is capped
+// regardless of destination. The rate is counted in redis and shared by every
+// instance, when redis is unavailable each instance fall back to its own limiter
+func (s *bulkInquiryService) waitBankRate(ctx context.Context, req dto.InquiryRequest, log *logrus.Entry) error {
+	bankCode, rate := s.bankRate(req)
+	key := fmt.Sprintf("bulk_ratelimit:%s", bankCode)
+
+	for {
+		result, err := s.rateRedis.AllowRate(ctx, key, rate)
+		if err != nil {
+			log.WithField("step", "wait_bank_rate").WithError(err).Warn("Shared bulk rate unavailable, fallback to instance rate limiter")
+			return s.limiters.Get(bankCode, rate).Wait(ctx)
+		}
+		if result.Allowed {
+			return nil
+		}
+		if err := retryhelper.Wait(ctx, result.RetryAfter); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *bulkInquiryService) bankRate(req dto.InquiryRequest) (string, int) {
+	bankCode := s.routeSvc.PrimaryPartnerBank(req.CompanyId, req.BankCode, req.Type)
+	rate := s.cfg.RatePerSecond
+	if bankConfig, ok := s.bankRepo.GetBankConfig(bankCode); ok && bankConfig.BulkRatePerSecond > 0 {
+		rate = bankConfig.BulkRatePerSecond
+	}
+	return bankCode, rate
+}
+
+func buildBulkJobResponse(job *entity.InquiryBulkJob, message string) *dto.BulkJobResponse {
+	data := dto.BulkJobData{
+		JobId:         job.JobId,
+		CompanyId:     job.MerchantCode,
+		JobStatus:     job.Status,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		SuccessRows:   job.SuccessRows,
+		FailedRows:    job.FailedRows,
+		CreatedAt:     timehelper.FormatTimeToISO7(job.CreatedAt),
+	}
+	if job.CompletedAt != nil {
+		data.CompletedAt = timehelper.FormatTimeToISO7(*job.CompletedAt)
+	}
+
+	return &dto.BulkJobResponse{
+		Status:  true,
+		Code:    "SUCCESS",
+		Message: message,
+		Source:  errorhelper.SourceInternal,
+		Data:    data,
+	}
+}
+
+func newBulkJobId(now time.Time) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("BLK%s%s", now.Format("20060102150405"), hex.EncodeToString(suffix))
+}
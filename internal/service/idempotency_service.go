@@ -0,0 +1,212 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/authorization"
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/jsonhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	idempotencyInFlightTTL  = 2 * time.Minute
+	idempotencyCompletedTTL = 24 * time.Hour
+	idempotencyPollInterval = 200 * time.Millisecond
+	idempotencyMaxWait      = 30 * time.Second
+)
+
+var (
+	ErrReferenceConflict   = errors.New("partner reference already used with different payload")
+	ErrReferenceInProgress = errors.New("request with the same partner reference is still in progress")
+)
+
+// InquiryHandler run the actual inquiry and return the HTTP status with its response
+type InquiryHandler func(ctx context.Context) (int, *dto.InquiryResponse)
+
+type IdempotencyService interface {
+	Execute(ctx context.Context, merchantCode, partnerRefNo string, req dto.InquiryRequest, handler InquiryHandler) (int, *dto.InquiryResponse, error)
+}
+
+type idempotencyService struct {
+	dbRepo    repository.IdempotencyRepository
+	redisRepo repository.IdempotencyRedisRepository
+}
+
+func NewIdempotencyService(dbRepo repository.IdempotencyRepository, redisRepo repository.IdempotencyRedisRepository) IdempotencyService {
+	return &idempotencyService{dbRepo, redisRepo}
+}
+
+func (s *idempotencyService) Execute(ctx context.Context, merchantCode, partnerRefNo string, req dto.InquiryRequest, handler InquiryHandler) (int, *dto.InquiryResponse, error) {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":     "idempotency_service",
+		"operation":   "execute_inquiry",
+		"merchant":    merchantCode,
+		"partner_ref": partnerRefNo,
+	})
+
+	requestHash := hashInquiryRequest(req)
+	key := idempotencyKey(merchantCode, partnerRefNo)
+	inFlight := string(jsonhelper.WriteToJson(dto.IdempotencyRecord{State: dto.IdempotencyInProgress, RequestHash: requestHash}))
+	waitUntil := time.Now().Add(idempotencyMaxWait)
+
+	for {
+		acquired, err := s.redisRepo.SetIfAbsent(ctx, key, inFlight, idempotencyInFlightTTL)
+		if err != nil {
+			log.WithField("step", "acquire_reference").WithError(err).Warn("Redis unavailable, fallback to database idempotency check")
+			return s.executeWithDB(ctx, merchantCode, partnerRefNo, requestHash, handler, log)
+		}
+
+		if acquired {
+			// redis record may have expired while database still keep the original response
+			status, response, found, err := s.findStored(ctx, merchantCode, partnerRefNo, requestHash)
+			if err != nil {
+				_ = s.redisRepo.Delete(context.WithoutCancel(ctx), key)
+				return 0, nil, err
+			}
+			if found {
+				record := completedIdempotencyRecord(requestHash, status, response)
+				_ = s.redisRepo.Set(context.WithoutCancel(ctx), key, string(jsonhelper.WriteToJson(record)), idempotencyCompletedTTL)
+				return status, response, nil
+			}
+			return s.run(ctx, key, merchantCode, partnerRefNo, requestHash, handler, log)
+		}
+
+		value, found, err := s.redisRepo.Get(ctx, key)
+		if err != nil {
+			log.WithField("step", "check_reference").WithError(err).Warn("Redis unavailable, fallback to database idempotency check")
+			return s.executeWithDB(ctx, merchantCode, partnerRefNo, requestHash, handler, log)
+		}
+		if !found {
+			// first request finished without result, take over the reference
+			continue
+		}
+
+		var record dto.IdempotencyRecord
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			return 0, nil, fmt.Errorf("invalid idempotency record: %w", err)
+		}
+
+		if record.RequestHash != requestHash {
+			log.WithField("step", "check_reference").Warn("Partner reference reused with different payload")
+			return 0, nil, ErrReferenceConflict
+		}
+
+		if record.State == dto.IdempotencyCompleted {
+			log.WithField("step", "check_reference").Info("Returning stored response of repeated partner reference")
+			if record.Response != nil {
+				record.Response.Data.BankResponseCode = record.BankResponseCode
+			}
+			return record.HttpStatus, record.Response, nil
+		}
+
+		if time.Now().After(waitUntil) {
+			return 0, nil, ErrReferenceInProgress
+		}
+
+		log.WithField("step", "check_reference").Info("Same partner reference is in progress, waiting for the result")
+		select {
+		case <-ctx.Done():
+			return 0, nil, ctx.Err()
+		case <-time.After(idempotencyPollInterval):
+		}
+	}
+}
+
+func (s *idempotencyService) run(ctx context.Context, key, merchantCode, partnerRefNo, requestHash string, handler InquiryHandler, log *logrus.Entry) (int, *dto.InquiryResponse, error) {
+	status, response := handler(ctx)
+	storeCtx := context.WithoutCancel(ctx)
+
+	// no result from the bank, release the reference so merchant can retry
+	if !response.Status && errorhelper.IsRetrySafe(response.Code) {
+		if err := s.redisRepo.Delete(storeCtx, key); err != nil {
+			log.WithField("step", "release_reference").WithError(err).Warn("Failed to release partner reference")
+		}
+		return status, response, nil
+	}
+
+	record := completedIdempotencyRecord(requestHash, status, response)
+	if err := s.redisRepo.Set(storeCtx, key, string(jsonhelper.WriteToJson(record)), idempotencyCompletedTTL); err != nil {
+		log.WithField("step", "store_response").WithError(err).Warn("Failed to store response in redis")
+	}
+
+	s.saveRecord(storeCtx, merchantCode, partnerRefNo, requestHash, status, response, log)
+	return status, response, nil
+}
+
+func (s *idempotencyService) executeWithDB(ctx context.Context, merchantCode, partnerRefNo, requestHash string, handler InquiryHandler, log *logrus.Entry) (int, *dto.InquiryResponse, error) {
+	status, response, found, err := s.findStored(ctx, merchantCode, partnerRefNo, requestHash)
+	if err != nil || found {
+		return status, response, err
+	}
+
+	status, response = handler(ctx)
+	if response.Status || !errorhelper.IsRetrySafe(response.Code) {
+		s.saveRecord(context.WithoutCancel(ctx), merchantCode, partnerRefNo, requestHash, status, response, log)
+	}
+	return status, response, nil
+}
+
+func (s *idempotencyService) findStored(ctx context.Context, merchantCode, partnerRefNo, requestHash string) (int, *dto.InquiryResponse, bool, error) {
+	stored, err := s.dbRepo.FindByReference(ctx, merchantCode, partnerRefNo)
+	if err != nil {
+		return 0, nil, false, fmt.Errorf("failed to check idempotency record: %w", err)
+	}
+	if stored == nil {
+		return 0, nil, false, nil
+	}
+
+	if stored.RequestHash != requestHash {
+		return 0, nil, false, ErrReferenceConflict
+	}
+
+	var response dto.InquiryResponse
+	if err := json.Unmarshal([]byte(stored.ResponseBody), &response); err != nil {
+		return 0, nil, false, fmt.Errorf("invalid stored response: %w", err)
+	}
+	response.Data.BankResponseCode = stored.BankResponseCode
+	return stored.HttpStatus, &response, true, nil
+}
+
+func (s *idempotencyService) saveRecord(ctx context.Context, merchantCode, partnerRefNo, requestHash string, status int, response *dto.InquiryResponse, log *logrus.Entry) {
+	record := &entity.InquiryIdempotency{
+		MerchantCode:       merchantCode,
+		PartnerReferenceNo: partnerRefNo,
+		RequestHash:        requestHash,
+		HttpStatus:         status,
+		ResponseBody:       string(jsonhelper.WriteToJson(response)),
+		BankResponseCode:   response.Data.BankResponseCode,
+		CreatedAt:          time.Now(),
+	}
+	if err := s.dbRepo.SaveRecord(ctx, record); err != nil {
+		log.WithField("step", "store_response").WithError(err).Warn("Failed to store response in database")
+	}
+}
+
+// completedIdempotencyRecord carry the bank response code outside the
+// response body, which never serialize it
+func completedIdempotencyRecord(requestHash string, status int, response *dto.InquiryResponse) dto.IdempotencyRecord {
+	return dto.IdempotencyRecord{
+		State:            dto.IdempotencyCompleted,
+		RequestHash:      requestHash,
+		HttpStatus:       status,
+		Response:         response,
+		BankResponseCode: response.Data.BankResponseCode,
+	}
+}
+
+func hashInquiryRequest(req dto.InquiryRequest) string {
+	return authorization.HashSHA256Hex(jsonhelper.WriteToJson(req))
+}
+
+func idempotencyKey(merchantCode, partnerRefNo string) string {
+	return fmt.Sprintf("idempotency:%s:%s", merchantCode, partnerRefNo)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/helper/jsonhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type InquiryCacheConfig struct {
+	PositiveTTL time.Duration
+	NegativeTTL time.Duration
+}
+
+type InquiryCacheService interface {
+	GetResult(ctx context.Context, req dto.InquiryRequest) (*dto.InquiryResponse, bool)
+	SaveResult(ctx context.Context, req dto.InquiryRequest, response *dto.InquiryResponse)
+}
+
+type inquiryCacheService struct {
+	cacheRepo repository.InquiryCacheRepository
+	cfg       InquiryCacheConfig
+}
+
+func NewInquiryCacheService(cacheRepo repository.InquiryCacheRepository, cfg InquiryCacheConfig) InquiryCacheService {
+	return &inquiryCacheService{cacheRepo, cfg}
+}
+
+func (s *inquiryCacheService) GetResult(ctx context.Context, req dto.InquiryRequest) (*dto.InquiryResponse, bool) {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "inquiry_cache_service",
+		"operation": "get_cached_result",
+	})
+
+	value, found, err := s.cacheRepo.GetResult(ctx, inquiryCacheKey(req))
+	if err != nil {
+		log.WithField("step", "get_cache").WithError(err).Warn("Failed to read inquiry cache, continue to bank")
+		return nil, false
+	}
+	if !found {
+		return nil, false
+	}
+
+	var record dto.InquiryCacheRecord
+	if err := json.Unmarshal([]byte(value), &record); err != nil || record.Response == nil {
+		log.WithField("step", "get_cache").WithError(err).Warn("Invalid inquiry cache record, continue to bank")
+		return nil, false
+	}
+
+	response := record.Response
+	response.Data.BankResponseCode = record.BankResponseCode
+	response.Data.Cached = true
+	response.Data.CacheAgeSeconds = int64(time.Since(record.CachedAt).Seconds())
+	return response, true
+}
+
+// SaveResult cache successful name and not found account with their own TTL,
+// other failures are never cached
+func (s *inquiryCacheService) SaveResult(ctx context.Context, req dto.InquiryRequest, response *dto.InquiryResponse) {
+	var ttl time.Duration
+	switch {
+	case response.Status:
+		ttl = s.cfg.PositiveTTL
+	case response.Code == "ACCOUNT_NOT_FOUND":
+		ttl = s.cfg.NegativeTTL
+	default:
+		return
+	}
+
+	record := dto.InquiryCacheRecord{Response: response, BankResponseCode: response.Data.BankResponseCode, CachedAt: time.Now()}
+	if err := s.cacheRepo.SetResult(ctx, inquiryCacheKey(req), string(jsonhelper.WriteToJson(record)), ttl); err != nil {
+		loghelper.Logger.WithFields(logrus.Fields{
+			"service":   "inquiry_cache_service",
+			"operation": "save_cached_result",
+		}).WithError(err).Warn("Failed to save inquiry result to cache")
+	}
+}
+
+func inquiryCacheKey(req dto.InquiryRequest) string {
+	return fmt.Sprintf("inquiry_cache:%s:%s:%s", req.BankCode, req.BeneficiaryAccount, req.Type)
+}
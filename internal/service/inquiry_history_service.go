@@ -0,0 +1,186 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/timehelper"
+	"briefcash-inquiry/internal/repository"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	historyDefaultLimit = 50
+	historyMaxLimit     = 500
+	historyDefaultRange = 7 * 24 * time.Hour
+	historyMaxRange     = 31 * 24 * time.Hour
+	historyDateLayout   = "2006-01-02"
+)
+
+var ErrInvalidHistoryFilter = errors.New("invalid inquiry history filter")
+
+type InquiryHistoryService interface {
+	SearchInquiries(ctx context.Context, req dto.InquiryHistoryRequest) (*dto.InquiryHistoryResponse, error)
+	ExportInquiries(ctx context.Context, req dto.InquiryHistoryRequest, handle func(dto.InquiryHistoryData) error) error
+}
+
+type inquiryHistoryService struct {
+	repo repository.InquiryRepository
+}
+
+func NewInquiryHistoryService(repo repository.InquiryRepository) InquiryHistoryService {
+	return &inquiryHistoryService{repo}
+}
+
+func (s *inquiryHistoryService) SearchInquiries(ctx context.Context, req dto.InquiryHistoryRequest) (*dto.InquiryHistoryResponse, error) {
+	filter, err := buildInquiryFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// fetch one more row to know whether another page exists
+	limit := filter.Limit
+	filter.Limit++
+	inquiries, err := s.repo.SearchInquiries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.InquiryHistoryResponse{
+		Status:  true,
+		Code:    "SUCCESS",
+		Message: "Inquiry history found",
+		Source:  errorhelper.SourceInternal,
+		Data:    make([]dto.InquiryHistoryData, 0, len(inquiries)),
+	}
+	if len(inquiries) > limit {
+		inquiries = inquiries[:limit]
+		response.NextCursor = encodeHistoryCursor(inquiries[limit-1].ID)
+	}
+	for i := range inquiries {
+		response.Data = append(response.Data, mapInquiryHistory(&inquiries[i]))
+	}
+	return response, nil
+}
+
+func (s *inquiryHistoryService) ExportInquiries(ctx context.Context, req dto.InquiryHistoryRequest, handle func(dto.InquiryHistoryData) error) error {
+	filter, err := buildInquiryFilter(req)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.ExportInquiries(ctx, filter, func(inquiry *entity.Inquiry) error {
+		return handle(mapInquiryHistory(inquiry))
+	})
+}
+
+// buildInquiryFilter validate history request, date range default to the last
+// seven days and is capped to keep the query on the indexed range
+func buildInquiryFilter(req dto.InquiryHistoryRequest) (entity.InquiryFilter, error) {
+	filter := entity.InquiryFilter{
+		MerchantCode:       req.CompanyId,
+		BankCode:           req.BankCode,
+		Status:             req.Status,
+		BeneficiaryAccount: req.BeneficiaryAccount,
+		PartnerReferenceNo: req.PartnerReferenceNo,
+		Limit:              req.Limit,
+	}
+
+	if filter.Status != "" && !entity.IsValidInquiryStatus(filter.Status) {
+		return filter, fmt.Errorf("%w: unknown status %s", ErrInvalidHistoryFilter, filter.Status)
+	}
+
+	var err error
+	if filter.DateFrom, err = parseHistoryDate(req.DateFrom, false); err != nil {
+		return filter, err
+	}
+	if filter.DateTo, err = parseHistoryDate(req.DateTo, true); err != nil {
+		return filter, err
+	}
+	if filter.DateTo.IsZero() {
+		filter.DateTo = time.Now()
+	}
+	if filter.DateFrom.IsZero() {
+		filter.DateFrom = filter.DateTo.Add(-historyDefaultRange)
+	}
+	if !filter.DateFrom.Before(filter.DateTo) {
+		return filter, fmt.Errorf("%w: date_from must be before date_to", ErrInvalidHistoryFilter)
+	}
+	if filter.DateTo.Sub(filter.DateFrom) > historyMaxRange {
+		return filter, fmt.Errorf("%w: date range exceeds %d days", ErrInvalidHistoryFilter, int(historyMaxRange.Hours()/24))
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = historyDefaultLimit
+	}
+	if filter.Limit > historyMaxLimit {
+		filter.Limit = historyMaxLimit
+	}
+
+	if req.Cursor != "" {
+		if filter.CursorID, err = decodeHistoryCursor(req.Cursor); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// parseHistoryDate accept a date or RFC3339 time, a date used as upper bound
+// include the whole day
+func parseHistoryDate(value string, endOfRange bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.ParseInLocation(historyDateLayout, value, time.FixedZone("WIB", 7*60*60)); err == nil {
+		if endOfRange {
+			date = date.AddDate(0, 0, 1)
+		}
+		return date, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date %s", ErrInvalidHistoryFilter, value)
+	}
+	return date, nil
+}
+
+func encodeHistoryCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeHistoryCursor(cursor string) (int64, error) {
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid cursor", ErrInvalidHistoryFilter)
+	}
+
+	id, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid cursor", ErrInvalidHistoryFilter)
+	}
+	return id, nil
+}
+
+func mapInquiryHistory(inquiry *entity.Inquiry) dto.InquiryHistoryData {
+	return dto.InquiryHistoryData{
+		MerchantCode:       inquiry.MerchantCode,
+		PartnerReferenceNo: inquiry.PartnerReferenceNo,
+		BeneficiaryAccount: inquiry.BeneficiaryAccount,
+		BankCode:           inquiry.BeneficiaryBankCode,
+		BeneficiaryName:    inquiry.BeneficiaryAccountName,
+		PartnerBankCode:    inquiry.PartnerBankCode,
+		InquiryType:        inquiry.InquiryType,
+		InquiryStatus:      inquiry.Status,
+		ErrorCode:          inquiry.ErrorCode,
+		BankResponseCode:   inquiry.BankResponseCode,
+		LatencyMs:          inquiry.LatencyMs,
+		InquiryDate:        timehelper.FormatTimeToISO7(inquiry.InquiryDate),
+	}
+}
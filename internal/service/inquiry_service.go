@@ -1,18 +1,24 @@
 package service
 
 import (
-	"briefcash-inquiry/internal/authorization"
 	"briefcash-inquiry/internal/dto"
 	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/breakerhelper"
 	"briefcash-inquiry/internal/helper/errorhelper"
 	"briefcash-inquiry/internal/helper/httphelper"
 	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/helper/retryhelper"
 	"briefcash-inquiry/internal/helper/routinghelper"
 	"briefcash-inquiry/internal/mapper"
 	"briefcash-inquiry/internal/repository"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"net"
 	"net/http"
-	"sync"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"context"
@@ -21,26 +27,42 @@ import (
 	"gorm.io/gorm"
 )
 
+var snapInvalidTokenCode = regexp.MustCompile(`^401\d{2}01$`)
+
+// inquiryTimeout bound the whole synchronous inquiry, retries, token refresh and
+// failover included, so the merchant is answered even when every bank is slow
+const inquiryTimeout = 30 * time.Second
+
 type InquiryService interface {
 	InquiryAccount(ctx context.Context, dto dto.InquiryRequest, partnerRefNo string) (*dto.InquiryResponse, error)
+	GetInquiryStatus(ctx context.Context, merchantCode, partnerRefNo string, refresh bool) (*dto.InquiryResponse, error)
 }
 
 type inquiryService struct {
-	repo     repository.InquiryRepository
-	tokenSvc TokenService
-	bankRepo BankPartner
-	db       *gorm.DB
+	repo      repository.InquiryRepository
+	tokenSvc  TokenService
+	bankRepo  BankPartner
+	routeSvc  RouteService
+	providers routinghelper.ProviderRegistry
+	breakers  *breakerhelper.BreakerRegistry
+	clients   *httphelper.ClientRegistry
+	merchants MerchantService
+	cacheSvc  InquiryCacheService
+	db        *gorm.DB
 }
 
 type inquiryContext struct {
 	Request      dto.InquiryRequest
 	BankConfig   *entity.BankConfig
+	Provider     routinghelper.Provider
+	Client       *httphelper.HttpClientHelper
 	PartnerRefNo string
+	ExternalId   string // X-EXTERNAL-ID of the last request sent
 	Context      context.Context
 }
 
-func NewInquiryService(repo repository.InquiryRepository, tokenSvc TokenService, bankRepo BankPartner, db *gorm.DB) InquiryService {
-	return &inquiryService{repo, tokenSvc, bankRepo, db}
+func NewInquiryService(repo repository.InquiryRepository, tokenSvc TokenService, bankRepo BankPartner, routeSvc RouteService, providers routinghelper.ProviderRegistry, breakers *breakerhelper.BreakerRegistry, clients *httphelper.ClientRegistry, merchants MerchantService, cacheSvc InquiryCacheService, db *gorm.DB) InquiryService {
+	return &inquiryService{repo, tokenSvc, bankRepo, routeSvc, providers, breakers, clients, merchants, cacheSvc, db}
 }
 
 func (is *inquiryService) InquiryAccount(ctx context.Context, req dto.InquiryRequest, externalId string) (*dto.InquiryResponse, error) {
@@ -50,78 +72,261 @@ func (is *inquiryService) InquiryAccount(ctx context.Context, req dto.InquiryReq
 		"bank_code":   req.BankCode,
 		"external_id": externalId,
 	})
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, inquiryTimeout)
+	defer cancel()
+
+	log.WithField("step", "persist_data").Info("Save pending inquiry to database")
+	inquiry, err := is.createPendingInquiry(ctx, req, externalId)
+	if err != nil {
+		log.WithField("step", "persist_data").WithError(err).Error("Failed to save inquiry to database")
+		return errorhelper.BuildErrorResponse(errorhelper.ErrorDetail{
+			Code:       "INTERNAL_SERVER_ERROR",
+			Message:    "Internal server error occured",
+			LogMessage: err.Error(),
+			Source:     errorhelper.SourceInternal,
+		}, "", err)
+	}
 
+	response, err := is.inquiryWithCache(ctx, req, externalId, log)
+	is.completeInquiry(context.WithoutCancel(ctx), inquiry, response, err, time.Since(start), log)
+	return response, err
+}
+
+func (is *inquiryService) inquiryWithCache(ctx context.Context, req dto.InquiryRequest, externalId string, log *logrus.Entry) (*dto.InquiryResponse, error) {
+	merchant, _ := is.merchants.GetMerchantConfig(req.CompanyId)
+	if !merchant.DisableInquiryCache {
+		log.WithField("step", "check_cache").Info("Checking cached inquiry result")
+		if cached, ok := is.cacheSvc.GetResult(ctx, req); ok {
+			log.WithField("step", "check_cache").Infof("Returning cached inquiry result, age %d seconds", cached.Data.CacheAgeSeconds)
+			if !cached.Status {
+				return cached, fmt.Errorf("cached inquiry result: %s", cached.Code)
+			}
+			return cached, nil
+		}
+	}
+
+	response, err := is.inquiryWithFailover(ctx, req, externalId, log)
+	is.cacheSvc.SaveResult(context.WithoutCancel(ctx), req, response)
+	return response, err
+}
+
+func (is *inquiryService) inquiryWithFailover(ctx context.Context, req dto.InquiryRequest, externalId string, log *logrus.Entry) (*dto.InquiryResponse, error) {
 	log.WithField("step", "get_bank_route").Info("Check available bank routes")
-	bankConfig := is.bankRepo.GetBankConfig(req.BankCode)
-	bankRoute := routinghelper.NewBankRouteRequest(req, &bankConfig, externalId)
-	log.Infof("Bank available, will send request from bank %s", bankConfig.BankName)
+	partnerBankCodes, err := is.routeSvc.ResolvePartnerBanks(req.CompanyId, req.BankCode, req.Type)
+	if err != nil {
+		log.WithField("step", "get_bank_route").WithError(err).Error("No inquiry route available for destination bank")
+		return errorhelper.BuildErrorResponse(errorhelper.ErrorDetail{
+			Code:       "ROUTE_NOT_FOUND",
+			Message:    "No route available for destination bank",
+			LogMessage: "Inquiry route not configured",
+			Source:     errorhelper.SourceInternal,
+		}, "", err)
+	}
 
-	data := inquiryContext{Request: req, BankConfig: &bankConfig, PartnerRefNo: externalId, Context: ctx}
-	var accessToken string
+	var response *dto.InquiryResponse
+	for i, partnerBankCode := range partnerBankCodes {
+		partnerLog := log.WithField("partner_bank_code", partnerBankCode)
+		response, err = is.inquiryPartner(ctx, req, externalId, partnerBankCode, partnerLog)
+		response.Data.PartnerBankCode = partnerBankCode
+		if err == nil || !errorhelper.IsRetrySafe(response.Code) || ctx.Err() != nil {
+			return response, err
+		}
 
-	log.WithField("step", "check_active_access_token").Info("Checking active access token in redis and database")
-	accessToken, err := is.tokenSvc.GetActiveAccessToken(ctx, bankConfig.BankName)
+		if i < len(partnerBankCodes)-1 {
+			partnerLog.WithField("step", "failover").WithError(err).
+				Warnf("Partner bank failed with %s, failover to partner bank %s", response.Code, partnerBankCodes[i+1])
+		}
+	}
+	return response, err
+}
 
+func (is *inquiryService) inquiryPartner(ctx context.Context, req dto.InquiryRequest, externalId, partnerBankCode string, log *logrus.Entry) (*dto.InquiryResponse, error) {
+	bankConfig, provider, err := is.resolvePartner(partnerBankCode)
 	if err != nil {
-		log.WithField("step", "get_new_access_token").Info("Get new access token from bank")
-		respToken, err := authorization.GetAccessToken(&bankConfig, log)
-		if err != nil {
-			log.WithField("step", "get_new_access_token").WithError(err).Error("Failed to get new access token from bank")
-			return is.handleInquiryResponse(&data, nil, 0, err, log)
+		log.WithField("step", "get_bank_route").WithError(err).Error("No provider available for bank")
+		return errorhelper.BuildErrorResponse(errorhelper.ErrorDetail{
+			Code:       "BANK_NOT_SUPPORTED",
+			Message:    "Bank is not supported",
+			LogMessage: "No inquiry provider registered for bank",
+			Source:     errorhelper.SourceInternal,
+		}, "", err)
+	}
+	log.Infof("Bank available, will send request from bank %s", provider.Name())
+
+	data := inquiryContext{
+		Request:      req,
+		BankConfig:   &bankConfig,
+		Provider:     provider,
+		Client:       bankHttpClient(is.clients, &bankConfig),
+		PartnerRefNo: externalId,
+		Context:      ctx,
+	}
+
+	log.WithField("step", "check_circuit_breaker").Info("Checking circuit breaker state of partner bank")
+	breaker := is.breakers.Get(bankConfig.BankCode)
+	if err := breaker.Allow(); err != nil {
+		log.WithField("step", "check_circuit_breaker").WithError(err).Warn("Circuit breaker is open, skip sending request to bank")
+		return errorhelper.BuildErrorResponse(errorhelper.ErrorDetail{
+			Code:       "BANK_CIRCUIT_OPEN",
+			Message:    "Bank is temporarily unavailable",
+			LogMessage: "Circuit breaker open for partner bank",
+			Source:     errorhelper.SourceInternal,
+		}, "", err)
+	}
+
+	response, err := is.callPartner(&data, log)
+	switch {
+	case ctx.Err() != nil:
+		// cancelled by the caller, the bank health is unknown
+		breaker.Abort()
+	case err != nil && errorhelper.IsRetrySafe(response.Code):
+		breaker.RecordFailure()
+	default:
+		breaker.RecordSuccess()
+	}
+	return response, err
+}
+
+func (is *inquiryService) callPartner(data *inquiryContext, log *logrus.Entry) (*dto.InquiryResponse, error) {
+	ctx, bankConfig := data.Context, data.BankConfig
+
+	accessToken, err := is.partnerAccessToken(ctx, bankConfig, data.Provider, data.Client, log)
+	if err != nil {
+		return is.handleInquiryResponse(data, nil, 0, err, log)
+	}
+
+	resp, httpStatus, err := is.sendWithRetry(data, accessToken, log)
+	if err == nil && is.isTokenRejected(data, httpStatus, resp) {
+		log.WithField("step", "invalidate_access_token").Warn("Bank rejected access token, invalidating token and retrying once")
+		if err := is.tokenSvc.InvalidateAccessToken(ctx, bankConfig.BankCode, bankConfig.ClientKey, accessToken); err != nil {
+			log.WithField("step", "invalidate_access_token").WithError(err).Warn("Failed to invalidate rejected access token")
 		}
 
-		token := &entity.AccessToken{
-			AccessToken: respToken.AccessToken,
-			TokenType:   "Bearer",
-			ExpiresIn:   respToken.ExpiresIn,
-			ExpiresDate: time.Now().Add(time.Duration(respToken.ExpiresIn-30) * time.Second),
+		accessToken, err = is.partnerAccessToken(ctx, bankConfig, data.Provider, data.Client, log)
+		if err != nil {
+			return is.handleInquiryResponse(data, nil, 0, err, log)
 		}
 
-		log.WithField("step", "get_new_access_token").Info("Access token retrieved, saving data to database and redis, running on goroutine")
-		errorChn := make(chan error, 2)
-		var wg sync.WaitGroup
-		wg.Add(2)
+		resp, httpStatus, err = is.sendWithRetry(data, accessToken, log)
+	}
 
-		go func() {
-			defer wg.Done()
-			err := is.tokenSvc.SaveAccessTokenDB(ctx, token)
-			if err != nil {
-				errorChn <- fmt.Errorf("failed to save access token to database: %v", err)
-			}
-		}()
+	response, err := is.handleInquiryResponse(data, resp, httpStatus, err, log)
+	response.Data.ExternalId = data.ExternalId
+	return response, err
+}
 
-		go func() {
-			defer wg.Done()
-			err := is.tokenSvc.SaveAccessTokenRedis(ctx, bankConfig.BankName, token)
-			if err != nil {
-				errorChn <- fmt.Errorf("failed to save access token to redis: %v", err)
-			}
-		}()
+// partnerAccessToken get the access token of the partner, provider
+// authenticating with static credential is sent without any
+func (is *inquiryService) partnerAccessToken(ctx context.Context, cfg *entity.BankConfig, provider routinghelper.Provider, client *httphelper.HttpClientHelper, log *logrus.Entry) (string, error) {
+	if routinghelper.UsesStaticAuth(provider) {
+		return "", nil
+	}
+	return is.tokenSvc.GetOrRefreshAccessToken(ctx, cfg, bankTokenFetcher(provider, client), log)
+}
 
-		wg.Wait()
-		close(errorChn)
+// resolvePartner load config and provider of the partner bank executing the inquiry
+func (is *inquiryService) resolvePartner(partnerBankCode string) (entity.BankConfig, routinghelper.Provider, error) {
+	bankConfig, ok := is.bankRepo.GetBankConfig(partnerBankCode)
+	if !ok {
+		return entity.BankConfig{}, nil, fmt.Errorf("partner bank %s is not configured", partnerBankCode)
+	}
 
-		for er := range errorChn {
-			log.WithField("step", "get_new_access_token").WithError(er).Warn("Failed to save new access token (Redis/DB)")
+	provider, err := is.providers.Resolve(bankConfig.ProviderKey())
+	if err != nil {
+		return entity.BankConfig{}, nil, err
+	}
+	return bankConfig, provider, nil
+}
+
+// sendWithRetry resend transport failure and retryable bank response with backoff,
+// X-EXTERNAL-ID is kept for bank requiring idempotency, otherwise a new one is used.
+// Partner reference is the first external id unless the bank only accept digits
+func (is *inquiryService) sendWithRetry(data *inquiryContext, accessToken string, log *logrus.Entry) ([]byte, int, error) {
+	policy := bankRetryPolicy(data.BankConfig)
+	externalId := data.PartnerRefNo
+	if routinghelper.RequiresNumericExternalId(data.Provider) && !isNumeric(externalId) {
+		externalId = newExternalId()
+	}
+
+	var (
+		resp       []byte
+		httpStatus int
+		err        error
+	)
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		attemptLog := log.WithField("attempt", attempt)
+		if attempt > 1 && !data.BankConfig.IdempotentExtId {
+			externalId = newExternalId()
+		}
+
+		data.ExternalId = externalId
+		resp, httpStatus, err = is.sendInquiry(data, accessToken, externalId, attemptLog)
+		if !is.isRetryable(data, policy, resp, httpStatus, err) || attempt == policy.MaxAttempts {
+			break
 		}
 
-		log.WithField("step", "get_new_access_token").Info("Saving data is done")
-		accessToken = respToken.AccessToken
+		delay := policy.Backoff(attempt)
+		attemptLog.WithFields(logrus.Fields{
+			"step":        "retry_request",
+			"http_status": httpStatus,
+			"delay":       delay.String(),
+		}).WithError(err).Warn("Retryable failure from bank, retrying request")
 
-		if accessToken == "" {
-			return is.handleInquiryResponse(&data, nil, 0, fmt.Errorf("missing access token after refresh"), log)
+		if waitErr := retryhelper.Wait(data.Context, delay); waitErr != nil {
+			attemptLog.WithField("step", "retry_request").WithError(waitErr).Warn("Stop retrying request")
+			break
 		}
 	}
+	return resp, httpStatus, err
+}
 
+func (is *inquiryService) isRetryable(data *inquiryContext, policy retryhelper.RetryPolicy, resp []byte, httpStatus int, err error) bool {
+	if err != nil {
+		return data.Context.Err() == nil
+	}
+
+	var responseCode string
+	if mapData, e := is.parseBankResponse(data, resp, httpStatus); e == nil {
+		responseCode = mapData.ResponseCode
+		httpStatus = mapData.EffectiveStatus(httpStatus)
+	}
+	return policy.IsRetryable(httpStatus, responseCode)
+}
+
+func (is *inquiryService) sendInquiry(data *inquiryContext, accessToken, externalId string, log *logrus.Entry) ([]byte, int, error) {
 	log.WithField("step", "set_param_request").Info("Setting up url, payload, and http header parameters")
-	url := bankRoute.GetUrl()
-	payload := bankRoute.BuildBodyRequest()
-	headers := bankRoute.GetHeaders(accessToken, externalId, &bankConfig, payload)
+	url := data.Provider.GetUrl(data.BankConfig, data.Request)
+	payload := data.Provider.BuildBodyRequest(data.BankConfig, data.Request)
+	headers := data.Provider.GetHeaders(data.BankConfig, data.Request, accessToken, externalId, payload)
 
 	log.WithField("step", "send_request").Info("Send request inquiry to destination bank")
-	client := httphelper.NewHttpClientHelper(10 * time.Second)
-	resp, httpStatus, err := client.SendRequest("POST", url, payload, headers)
-	return is.handleInquiryResponse(&data, resp, httpStatus, err, log)
+	return data.Client.SendRequest(data.Context, "POST", url, payload, headers)
+}
+
+// isTokenRejected detect stale or revoked access token, either by HTTP 401
+// (returned or derived from the bank response code)
+// or by SNAP response code 401xx01 (Access Token Invalid)
+func (is *inquiryService) isTokenRejected(data *inquiryContext, httpStatus int, respData []byte) bool {
+	if routinghelper.UsesStaticAuth(data.Provider) {
+		// there is no token to invalidate, 401 is a credential error
+		return false
+	}
+
+	if httpStatus == http.StatusUnauthorized {
+		return true
+	}
+
+	if respData == nil {
+		return false
+	}
+
+	mapData, err := is.parseBankResponse(data, respData, httpStatus)
+	if err != nil {
+		return false
+	}
+	return mapData.EffectiveStatus(httpStatus) == http.StatusUnauthorized || snapInvalidTokenCode.MatchString(mapData.ResponseCode)
 }
 
 func (is *inquiryService) handleInquiryResponse(data *inquiryContext, respData []byte, httpStatus int, er error, log *logrus.Entry) (*dto.InquiryResponse, error) {
@@ -132,8 +337,8 @@ func (is *inquiryService) handleInquiryResponse(data *inquiryContext, respData [
 	}
 
 	log.WithField("step", "parse_response").Info("Parsing and validating response data from bank")
-	mapData, err := is.parseBankResponse(respData, data.BankConfig, httpStatus)
-	if err != nil {
+	mapData, err := is.parseBankResponse(data, respData, httpStatus)
+	if err != nil && httpStatus == http.StatusOK {
 		log.WithField("step", "parse_response").WithError(err).Error("Failed to parsing bank response, please check response format")
 		return errorhelper.BuildErrorResponse(errorhelper.ErrorDetail{
 			Code:       "BANK_FORMAT_ERROR",
@@ -144,19 +349,11 @@ func (is *inquiryService) handleInquiryResponse(data *inquiryContext, respData [
 	}
 
 	log.WithField("step", "handle_bank_error").Info("Evaluating HTTP response status from bank")
+	httpStatus = mapData.EffectiveStatus(httpStatus)
 	if httpStatus != http.StatusOK {
-		return is.handleBankError(httpStatus, mapData, log)
-	}
-
-	log.WithField("step", "persist_data").Info("Save inquiry response to database")
-	if err := is.persistInquiry(data, mapData); err != nil {
-		log.WithField("step", "persist_data").WithError(err).Error("Failed to save inquiry to database")
-		return errorhelper.BuildErrorResponse(errorhelper.ErrorDetail{
-			Code:       "INTERNAL_SERVER_ERROR",
-			Message:    "Internal server error occured",
-			LogMessage: err.Error(),
-			Source:     errorhelper.SourceInternal,
-		}, "", err)
+		response, err := is.handleBankError(httpStatus, mapData, log)
+		response.Data.BankResponseCode = mapData.ResponseCode
+		return response, err
 	}
 
 	log.WithField("step", "build_response").Info("Map inquiry response to client")
@@ -188,10 +385,8 @@ func (is *inquiryService) handleTransportError(err error, respData []byte, log *
 	return nil, nil
 }
 
-func (is *inquiryService) parseBankResponse(bankResp []byte, bankCfg *entity.BankConfig, httpStatus int) (mapper.BankResponseData, error) {
-	routeResp := routinghelper.NewBankRouteResponse(bankCfg, httpStatus)
-	mapData := routeResp.MapResponse(bankResp)
-	return mapData, nil
+func (is *inquiryService) parseBankResponse(data *inquiryContext, bankResp []byte, httpStatus int) (mapper.BankResponseData, error) {
+	return data.Provider.MapResponse(data.BankConfig, data.Request, httpStatus, bankResp)
 }
 
 func (is *inquiryService) handleBankError(httpStatus int, mapData mapper.BankResponseData, log *logrus.Entry) (*dto.InquiryResponse, error) {
@@ -210,17 +405,95 @@ func (is *inquiryService) saveInquiry(ctx context.Context, inq *entity.Inquiry)
 	})
 }
 
-func (is *inquiryService) persistInquiry(data *inquiryContext, mapData mapper.BankResponseData) error {
+// createPendingInquiry record the inquiry before any bank call, so every
+// request leaves a trace even when the result never comes back
+func (is *inquiryService) createPendingInquiry(ctx context.Context, req dto.InquiryRequest, partnerRefNo string) (*entity.Inquiry, error) {
+	now := time.Now()
 	inquiry := &entity.Inquiry{
-		MerchantCode:           data.Request.CompanyId,
-		PartnerReferenceNo:     data.PartnerRefNo,
-		BeneficiaryAccount:     data.Request.BeneficiaryAccount,
-		BeneficiaryBankCode:    data.Request.BankCode,
-		BeneficiaryAccountName: mapData.AccountName,
-		InquiryDate:            time.Now(),
-		Status:                 "SUCCESS",
+		MerchantCode:        req.CompanyId,
+		PartnerReferenceNo:  partnerRefNo,
+		BeneficiaryAccount:  req.BeneficiaryAccount,
+		BeneficiaryBankCode: req.BankCode,
+		InquiryType:         req.Type,
+		InquiryDate:         now,
+		Status:              entity.InquiryStatusPending,
+		UpdatedAt:           now,
+	}
+	if err := is.saveInquiry(ctx, inquiry); err != nil {
+		return nil, err
+	}
+	return inquiry, nil
+}
+
+func (is *inquiryService) completeInquiry(ctx context.Context, inquiry *entity.Inquiry, response *dto.InquiryResponse, er error, latency time.Duration, log *logrus.Entry) {
+	inquiry.PartnerBankCode = response.Data.PartnerBankCode
+	inquiry.ExternalId = response.Data.ExternalId
+	inquiry.BankResponseCode = response.Data.BankResponseCode
+	inquiry.Cached = response.Data.Cached
+	inquiry.LatencyMs = latency.Milliseconds()
+	if response.Status {
+		inquiry.BeneficiaryAccountName = response.Data.BeneficiaryName
+	} else {
+		inquiry.ErrorCode = response.Code
+	}
+
+	status := inquiryResultStatus(response, er)
+	log.WithField("step", "persist_data").Infof("Update inquiry status to %s", status)
+	if err := is.transitionInquiry(ctx, inquiry, status); err != nil {
+		log.WithField("step", "persist_data").WithError(err).Error("Failed to update inquiry status")
+	}
+}
+
+// transitionInquiry move stored inquiry forward to the given status along with its result fields
+func (is *inquiryService) transitionInquiry(ctx context.Context, inquiry *entity.Inquiry, status string) error {
+	if !inquiry.CanTransitionTo(status) {
+		return fmt.Errorf("invalid inquiry status transition from %s to %s", inquiry.Status, status)
+	}
+
+	previous := inquiry.Status
+	inquiry.Status = status
+	inquiry.UpdatedAt = time.Now()
+	if err := is.repo.UpdateStatus(ctx, inquiry, previous); err != nil {
+		inquiry.Status = previous
+		return err
+	}
+	return nil
+}
+
+// inquiryResultStatus classify the inquiry response, failure where the bank may
+// have processed the request without us knowing the result is kept inconclusive
+func inquiryResultStatus(response *dto.InquiryResponse, err error) string {
+	switch {
+	case response.Status:
+		return entity.InquiryStatusSuccess
+	case response.Code == "ACCOUNT_NOT_FOUND":
+		return entity.InquiryStatusNotFound
+	case response.Code == "BANK_TIMEOUT" || isTimeoutError(err):
+		return entity.InquiryStatusTimeout
+	case unknownResultCodes[response.Code]:
+		return entity.InquiryStatusUnknown
+	default:
+		return entity.InquiryStatusBankError
 	}
-	return is.saveInquiry(data.Context, inquiry)
+}
+
+var unknownResultCodes = map[string]bool{
+	"INTERNAL_CONNECTION_ERROR":       true,
+	"BANK_NO_RESPONSE":                true,
+	"BANK_FORMAT_ERROR":               true,
+	"BANK_INTERNAL_ERROR":             true,
+	"BANK_BAD_GATEWAY":                true,
+	"BANK_PENDING":                    true,
+	errorhelper.DefaultBankError.Code: true,
+}
+
+func isTimeoutError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
 }
 
 func (is *inquiryService) buildSuccessResponse(data *inquiryContext, mapData mapper.BankResponseData) (*dto.InquiryResponse, error) {
@@ -233,6 +506,70 @@ func (is *inquiryService) buildSuccessResponse(data *inquiryContext, mapData map
 			BeneficiaryAccount: data.Request.BeneficiaryAccount,
 			BankCode:           data.Request.BankCode,
 			BeneficiaryName:    mapData.AccountName,
+			BankResponseCode:   mapData.ResponseCode,
 		},
 	}, nil
 }
+
+// bankHttpClient return the shared client of a bank, tuned by its partner settings
+func bankHttpClient(clients *httphelper.ClientRegistry, cfg *entity.BankConfig) *httphelper.HttpClientHelper {
+	clientCfg := httphelper.DefaultClientConfig
+	if cfg.HttpTimeoutMs > 0 {
+		clientCfg.Timeout = time.Duration(cfg.HttpTimeoutMs) * time.Millisecond
+	}
+	if cfg.MaxConnsPerHost > 0 {
+		clientCfg.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+	return clients.Get(cfg.BankCode, clientCfg)
+}
+
+// bankRetryPolicy override default retry policy with partner settings of the bank
+func bankRetryPolicy(cfg *entity.BankConfig) retryhelper.RetryPolicy {
+	policy := retryhelper.DefaultRetryPolicy
+	if cfg.RetryMaxAttempts > 0 {
+		policy.MaxAttempts = cfg.RetryMaxAttempts
+	}
+	if cfg.RetryBaseDelayMs > 0 {
+		policy.BaseDelay = time.Duration(cfg.RetryBaseDelayMs) * time.Millisecond
+	}
+	if cfg.RetryStatusCodes != "" {
+		policy.RetryableStatus = make(map[int]bool)
+		for _, value := range strings.Split(cfg.RetryStatusCodes, ",") {
+			if status, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+				policy.RetryableStatus[status] = true
+			}
+		}
+	}
+	if cfg.RetryResponseCodes != "" {
+		policy.RetryableResponseCodes = make(map[string]bool)
+		for _, value := range strings.Split(cfg.RetryResponseCodes, ",") {
+			policy.RetryableResponseCodes[strings.TrimSpace(value)] = true
+		}
+	}
+	return policy
+}
+
+// bankTokenFetcher bind the provider token request to the shared client of the bank
+func bankTokenFetcher(provider routinghelper.Provider, client *httphelper.HttpClientHelper) TokenFetcher {
+	return func(ctx context.Context, cfg *entity.BankConfig, log *logrus.Entry) (dto.SNAPAccessToken, error) {
+		return provider.GetAccessToken(ctx, client, cfg, log)
+	}
+}
+
+// newExternalId generate numeric X-EXTERNAL-ID for retry to non idempotent bank
+// and for bank rejecting non numeric reference
+func newExternalId() string {
+	return fmt.Sprintf("%s%08d", time.Now().Format("20060102150405"), rand.IntN(100000000))
+}
+
+func isNumeric(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/errorhelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/helper/routinghelper"
+	"briefcash-inquiry/internal/helper/timehelper"
+	"briefcash-inquiry/internal/mapper"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GetInquiryStatus return stored result of a previous inquiry of the merchant, an
+// inconclusive result is re-queried to the bank status API when refresh is requested
+func (is *inquiryService) GetInquiryStatus(ctx context.Context, merchantCode, partnerRefNo string, refresh bool) (*dto.InquiryResponse, error) {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":     "inquiry_service",
+		"operation":   "get_inquiry_status",
+		"merchant":    merchantCode,
+		"partner_ref": partnerRefNo,
+	})
+
+	log.WithField("step", "find_inquiry").Info("Find stored inquiry by partner reference")
+	inquiry, err := is.repo.FindByReference(ctx, merchantCode, partnerRefNo)
+	if err != nil {
+		log.WithField("step", "find_inquiry").WithError(err).Error("Failed to find inquiry data")
+		return errorhelper.BuildErrorResponse(errorhelper.ErrorDetail{
+			Code:       "INTERNAL_SERVER_ERROR",
+			Message:    "Internal server error occured",
+			LogMessage: err.Error(),
+			Source:     errorhelper.SourceInternal,
+		}, "", err)
+	}
+
+	if inquiry == nil {
+		return errorhelper.BuildErrorResponse(errorhelper.ErrorDetail{
+			Code:       "INQUIRY_NOT_FOUND",
+			Message:    "Inquiry not found",
+			LogMessage: "No inquiry stored for partner reference",
+			Source:     errorhelper.SourceClient,
+		}, "", nil)
+	}
+
+	if refresh && inquiry.IsInconclusive() {
+		log.WithField("step", "refresh_status").Info("Inquiry result is inconclusive, re-query bank status")
+		if err := is.refreshInquiryStatus(ctx, inquiry, log); err != nil {
+			log.WithField("step", "refresh_status").WithError(err).Warn("Failed to refresh inquiry status, returning stored result")
+		}
+	}
+
+	return buildInquiryStatusResponse(inquiry), nil
+}
+
+func (is *inquiryService) refreshInquiryStatus(ctx context.Context, inquiry *entity.Inquiry, log *logrus.Entry) error {
+	bankConfig, provider, err := is.resolvePartner(inquiry.PartnerBankCode)
+	if err != nil {
+		return err
+	}
+
+	if inquiry.ExternalId == "" {
+		return fmt.Errorf("inquiry %s was never sent to bank %s", inquiry.PartnerReferenceNo, provider.Name())
+	}
+
+	statusProvider, ok := provider.(routinghelper.StatusProvider)
+	if !ok || statusProvider.GetStatusUrl(&bankConfig) == "" {
+		return fmt.Errorf("status inquiry is not supported by bank %s", provider.Name())
+	}
+
+	client := bankHttpClient(is.clients, &bankConfig)
+	accessToken, err := is.partnerAccessToken(ctx, &bankConfig, provider, client, log)
+	if err != nil {
+		return err
+	}
+
+	payload := statusProvider.BuildStatusRequest(&bankConfig, inquiry)
+	headers := statusProvider.GetStatusHeaders(&bankConfig, accessToken, newExternalId(), payload)
+
+	log.WithField("step", "send_status_request").Info("Send status inquiry request to bank")
+	resp, httpStatus, err := client.SendRequest(ctx, "POST", statusProvider.GetStatusUrl(&bankConfig), payload, headers)
+	if err != nil {
+		return err
+	}
+
+	mapData, err := statusProvider.MapStatusResponse(&bankConfig, httpStatus, resp)
+	if err != nil && httpStatus == http.StatusOK {
+		return err
+	}
+
+	status, errorCode := statusInquiryResult(mapData.EffectiveStatus(httpStatus), mapData)
+	if status == entity.InquiryStatusUnknown {
+		return fmt.Errorf("bank status inquiry is still inconclusive, status: %d, message: %s", httpStatus, mapData.ResponseMessage)
+	}
+
+	if status == entity.InquiryStatusSuccess {
+		inquiry.BeneficiaryAccountName = mapData.AccountName
+	}
+	inquiry.BankResponseCode = mapData.ResponseCode
+	inquiry.ErrorCode = errorCode
+	return is.transitionInquiry(context.WithoutCancel(ctx), inquiry, status)
+}
+
+// statusInquiryResult classify the bank status answer, only a failed transaction or
+// an account reported not found is final, any other failure tell nothing about the inquiry
+func statusInquiryResult(httpStatus int, mapData mapper.BankResponseData) (string, string) {
+	switch {
+	case httpStatus == http.StatusNotFound:
+		return entity.InquiryStatusNotFound, errorhelper.ErrorMap[http.StatusNotFound].Code
+	case httpStatus != http.StatusOK:
+		return entity.InquiryStatusUnknown, ""
+	case mapper.IsTransactionSuccess(mapData.TransactionStatus):
+		return entity.InquiryStatusSuccess, ""
+	case mapper.IsTransactionFailed(mapData.TransactionStatus):
+		return entity.InquiryStatusBankError, errorhelper.DefaultBankError.Code
+	default:
+		return entity.InquiryStatusUnknown, ""
+	}
+}
+
+func buildInquiryStatusResponse(inquiry *entity.Inquiry) *dto.InquiryResponse {
+	return &dto.InquiryResponse{
+		Status:  true,
+		Code:    "SUCCESS",
+		Message: "Inquiry found",
+		Source:  errorhelper.SourceInternal,
+		Data: dto.InquiryData{
+			BeneficiaryAccount: inquiry.BeneficiaryAccount,
+			BankCode:           inquiry.BeneficiaryBankCode,
+			BeneficiaryName:    inquiry.BeneficiaryAccountName,
+			PartnerBankCode:    inquiry.PartnerBankCode,
+			PartnerReferenceNo: inquiry.PartnerReferenceNo,
+			InquiryStatus:      inquiry.Status,
+			InquiryDate:        timehelper.FormatTimeToISO7(inquiry.InquiryDate),
+			Cached:             inquiry.Cached,
+		},
+	}
+}
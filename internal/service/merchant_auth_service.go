@@ -0,0 +1,186 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/authorization"
+	"briefcash-inquiry/internal/dto"
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/timehelper"
+	"briefcash-inquiry/internal/repository"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var snapLocation = time.FixedZone("WIB", 7*60*60)
+
+const (
+	snapTokenSuccessCode      = "2007300"
+	snapTokenUnauthorizedCode = "4017300"
+)
+
+var (
+	ErrInvalidClient    = errors.New("unknown merchant client key")
+	ErrInvalidSignature = errors.New("invalid request signature")
+	ErrInvalidToken     = errors.New("invalid merchant access token")
+	ErrInvalidTimestamp = errors.New("invalid request timestamp")
+	ErrTimestampExpired = errors.New("request timestamp outside allowed window")
+	ErrDuplicateExtId   = errors.New("external id already used today")
+)
+
+type MerchantAuthConfig struct {
+	TokenTTL        time.Duration
+	TimestampWindow time.Duration
+}
+
+type MerchantAuthService interface {
+	IssueToken(ctx context.Context, clientKey, timestamp, signature string) (dto.SNAPAccessToken, error)
+	Authenticate(ctx context.Context, accessToken string) (entity.MerchantConfig, error)
+	VerifySignature(merchant entity.MerchantConfig, httpMethod, relativeUrl, accessToken string, body []byte, timestamp, signature string) error
+	CheckReplay(ctx context.Context, merchantCode, timestamp, externalId string) error
+}
+
+type merchantAuthService struct {
+	merchants  MerchantService
+	redisRepo  repository.MerchantTokenRedisRepository
+	replayRepo repository.ReplayRedisRepository
+	cfg        MerchantAuthConfig
+}
+
+func NewMerchantAuthService(merchants MerchantService, redisRepo repository.MerchantTokenRedisRepository, replayRepo repository.ReplayRedisRepository, cfg MerchantAuthConfig) MerchantAuthService {
+	return &merchantAuthService{merchants, redisRepo, replayRepo, cfg}
+}
+
+// IssueToken verify the asymmetric signature of clientKey|timestamp against the
+// merchant registered public key, the same way bank verify our token request
+func (s *merchantAuthService) IssueToken(ctx context.Context, clientKey, timestamp, signature string) (dto.SNAPAccessToken, error) {
+	merchant, ok := s.merchants.GetMerchantByClientKey(clientKey)
+	if !ok || merchant.PublicKey == "" {
+		return unauthorizedToken("Unknown client"), ErrInvalidClient
+	}
+
+	if _, err := s.checkTimestamp(timestamp); err != nil {
+		return unauthorizedToken("Invalid timestamp"), err
+	}
+
+	stringToSign := fmt.Sprintf("%s|%s", clientKey, timestamp)
+	if err := authorization.VerifyRSASignature([]byte(merchant.PublicKey), stringToSign, signature); err != nil {
+		return unauthorizedToken("Invalid signature"), fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	accessToken, err := newMerchantAccessToken()
+	if err != nil {
+		return dto.SNAPAccessToken{}, err
+	}
+	if err := s.redisRepo.SaveToken(ctx, merchantTokenKey(accessToken), merchant.MerchantCode, s.cfg.TokenTTL); err != nil {
+		return dto.SNAPAccessToken{}, err
+	}
+
+	return dto.SNAPAccessToken{
+		ResponseCode:    snapTokenSuccessCode,
+		ResponseMessage: "Successful",
+		AccessToken:     accessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       int16(s.cfg.TokenTTL.Seconds()),
+	}, nil
+}
+
+func (s *merchantAuthService) Authenticate(ctx context.Context, accessToken string) (entity.MerchantConfig, error) {
+	merchantCode, found, err := s.redisRepo.FindMerchantCode(ctx, merchantTokenKey(accessToken))
+	if err != nil {
+		return entity.MerchantConfig{}, err
+	}
+	if !found {
+		return entity.MerchantConfig{}, ErrInvalidToken
+	}
+
+	merchant, ok := s.merchants.GetMerchantConfig(merchantCode)
+	if !ok {
+		return entity.MerchantConfig{}, ErrInvalidToken
+	}
+	return merchant, nil
+}
+
+// VerifySignature check the symmetric signature of a transaction request, body
+// is minified before hashing as required by SNAP
+func (s *merchantAuthService) VerifySignature(merchant entity.MerchantConfig, httpMethod, relativeUrl, accessToken string, body []byte, timestamp, signature string) error {
+	if _, err := timehelper.FormatISO7ToTime(timestamp); err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	minified := body
+	if len(body) > 0 {
+		var buffer bytes.Buffer
+		if err := json.Compact(&buffer, body); err == nil {
+			minified = buffer.Bytes()
+		}
+	}
+
+	expected := authorization.HashSignature(httpMethod, relativeUrl, accessToken, authorization.HashSHA256Hex(minified), timestamp, merchant.ClientSecret)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// CheckReplay reject stale timestamp and external id reused by the merchant on
+// the same day, following SNAP rule that X-EXTERNAL-ID is unique per day. The
+// day is taken from the signed X-TIMESTAMP in WIB, not from our clock
+func (s *merchantAuthService) CheckReplay(ctx context.Context, merchantCode, timestamp, externalId string) error {
+	requestTime, err := s.checkTimestamp(timestamp)
+	if err != nil {
+		return err
+	}
+
+	requestDay := requestTime.In(snapLocation)
+	endOfDay := time.Date(requestDay.Year(), requestDay.Month(), requestDay.Day()+1, 0, 0, 0, 0, snapLocation)
+	key := fmt.Sprintf("external_id:%s:%s:%s", merchantCode, requestDay.Format("20060102"), externalId)
+
+	// a replay carry the same timestamp so it land on the same key, keep the key
+	// until that timestamp leave the window, which is at most end of its day plus window
+	claimed, err := s.replayRepo.ClaimKey(ctx, key, time.Until(endOfDay)+s.cfg.TimestampWindow)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrDuplicateExtId
+	}
+	return nil
+}
+
+func (s *merchantAuthService) checkTimestamp(timestamp string) (time.Time, error) {
+	requestTime, err := timehelper.FormatISO7ToTime(timestamp)
+	if err != nil {
+		return time.Time{}, ErrInvalidTimestamp
+	}
+
+	drift := time.Since(requestTime)
+	if drift > s.cfg.TimestampWindow || drift < -s.cfg.TimestampWindow {
+		return time.Time{}, ErrTimestampExpired
+	}
+	return requestTime, nil
+}
+
+func unauthorizedToken(reason string) dto.SNAPAccessToken {
+	return dto.SNAPAccessToken{
+		ResponseCode:    snapTokenUnauthorizedCode,
+		ResponseMessage: "Unauthorized. " + reason,
+	}
+}
+
+func newMerchantAccessToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate access token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+func merchantTokenKey(accessToken string) string {
+	return "merchant_token:" + authorization.HashSHA256Hex([]byte(accessToken))
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/repository"
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type MerchantService interface {
+	LoadAllMerchant(ctx context.Context) error
+	GetMerchantConfig(merchantCode string) (entity.MerchantConfig, bool)
+	GetMerchantByClientKey(clientKey string) (entity.MerchantConfig, bool)
+}
+
+type merchantService struct {
+	mu            sync.RWMutex
+	dbRepo        repository.MerchantRepository
+	merchantCache map[string]entity.MerchantConfig
+	clientKeys    map[string]string
+}
+
+func NewMerchantService(dbRepo repository.MerchantRepository) MerchantService {
+	return &merchantService{
+		dbRepo:        dbRepo,
+		merchantCache: make(map[string]entity.MerchantConfig),
+		clientKeys:    make(map[string]string),
+	}
+}
+
+func (s *merchantService) LoadAllMerchant(ctx context.Context) error {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "merchant_service",
+		"operation": "load_merchant_config",
+	})
+
+	log.WithField("step", "get_data_db").Info("Get merchant settings from db")
+	merchants, err := s.dbRepo.FindAll(ctx)
+	if err != nil {
+		log.WithField("step", "get_data_db").WithError(err).Error("Failed to fetch merchant settings from database")
+		return err
+	}
+
+	log.WithField("step", "caching_config").Infof("Cache merchant settings to memory, with total data %d", len(merchants))
+	s.mu.Lock()
+	for _, merchant := range merchants {
+		s.merchantCache[merchant.MerchantCode] = merchant
+		if merchant.ClientKey != "" {
+			s.clientKeys[merchant.ClientKey] = merchant.MerchantCode
+		}
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *merchantService) GetMerchantConfig(merchantCode string) (entity.MerchantConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	merchant, ok := s.merchantCache[merchantCode]
+	return merchant, ok
+}
+
+func (s *merchantService) GetMerchantByClientKey(clientKey string) (entity.MerchantConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	merchantCode, ok := s.clientKeys[clientKey]
+	if !ok {
+		return entity.MerchantConfig{}, false
+	}
+	merchant, ok := s.merchantCache[merchantCode]
+	return merchant, ok
+}
@@ -12,7 +12,8 @@ import (
 
 type BankPartner interface {
 	LoadAllBankPartner(ctx context.Context) error
-	GetBankConfig(bankCode string) entity.BankConfig
+	GetBankConfig(bankCode string) (entity.BankConfig, bool)
+	GetAllBankConfig() []entity.BankConfig
 }
 
 type bankPartner struct {
@@ -42,6 +43,13 @@ func (s *bankPartner) LoadAllBankPartner(ctx context.Context) error {
 	}
 
 	log.WithField("step", "caching_config").Infof("Cache data bank config to memory, with total data %d", len(banks))
+	for _, bank := range banks {
+		if err := validateAdditionalInfo(&bank); err != nil {
+			log.WithField("step", "validate_config").WithError(err).Error("Bank additional info setting is not a valid JSON object")
+			return err
+		}
+	}
+
 	s.mu.Lock()
 	for _, bank := range banks {
 		s.bankCache[bank.BankCode] = bank
@@ -50,7 +58,18 @@ func (s *bankPartner) LoadAllBankPartner(ctx context.Context) error {
 	return nil
 }
 
-func (s *bankPartner) GetBankConfig(bankCode string) entity.BankConfig {
+// validateAdditionalInfo reject malformed additionalInfo setting on load, the
+// bank would otherwise receive an empty additionalInfo on every inquiry
+func validateAdditionalInfo(bank *entity.BankConfig) error {
+	for _, internal := range []bool{true, false} {
+		if _, err := bank.InquiryAdditionalInfo(internal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *bankPartner) GetBankConfig(bankCode string) (entity.BankConfig, bool) {
 	log := loghelper.Logger.WithFields(logrus.Fields{
 		"service":   "partner_service",
 		"operation": "load_bank_partner_config",
@@ -63,11 +82,21 @@ func (s *bankPartner) GetBankConfig(bankCode string) entity.BankConfig {
 	bank, ok := s.bankCache[bankCode]
 
 	if !ok {
-		log.WithField("step", "get_bank_config").
-			Warnf("Bank config not found for %s, fallback to default Bank BCA", bankCode)
-		return s.bankCache["014"]
+		log.WithField("step", "get_bank_config").Warnf("Bank config not found for %s", bankCode)
+		return entity.BankConfig{}, false
 	}
 
 	log.WithField("step", "get_bank_config").Infof("Bank %s is selected", bank.BankName)
-	return bank
+	return bank, true
+}
+
+func (s *bankPartner) GetAllBankConfig() []entity.BankConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	banks := make([]entity.BankConfig, 0, len(s.bankCache))
+	for _, bank := range s.bankCache {
+		banks = append(banks, bank)
+	}
+	return banks
 }
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/helper/retryhelper"
+	"briefcash-inquiry/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// rateLimitMaxWait is the longest a background worker sleep for a merchant
+// limit, anything longer means the daily quota is used up
+const rateLimitMaxWait = time.Minute
+
+var ErrRateLimited = errors.New("merchant rate limit exceeded")
+
+// RateLimitDecision summarize every limit checked for a request, the values
+// come from the most restrictive one
+type RateLimitDecision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+type RateLimitConfig struct {
+	DefaultPerSecond int
+	DefaultPerDay    int
+}
+
+type RateLimitService interface {
+	LoadAllLimits(ctx context.Context) error
+	Check(ctx context.Context, merchantCode, bankCode, inquiryType string) (RateLimitDecision, error)
+	Wait(ctx context.Context, merchantCode, bankCode, inquiryType string) error
+}
+
+type rateLimitService struct {
+	mu         sync.RWMutex
+	dbRepo     repository.RateLimitRepository
+	redisRepo  repository.RateLimitRedisRepository
+	routeSvc   RouteService
+	cfg        RateLimitConfig
+	limitCache map[string]entity.MerchantRateLimit
+}
+
+func NewRateLimitService(dbRepo repository.RateLimitRepository, redisRepo repository.RateLimitRedisRepository, routeSvc RouteService, cfg RateLimitConfig) RateLimitService {
+	return &rateLimitService{
+		dbRepo:     dbRepo,
+		redisRepo:  redisRepo,
+		routeSvc:   routeSvc,
+		cfg:        cfg,
+		limitCache: make(map[string]entity.MerchantRateLimit),
+	}
+}
+
+func (s *rateLimitService) LoadAllLimits(ctx context.Context) error {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "rate_limit_service",
+		"operation": "load_rate_limit",
+	})
+
+	log.WithField("step", "get_data_db").Info("Get merchant rate limits from db")
+	limits, err := s.dbRepo.FindAll(ctx)
+	if err != nil {
+		log.WithField("step", "get_data_db").WithError(err).Error("Failed to fetch merchant rate limits from database")
+		return err
+	}
+
+	log.WithField("step", "caching_limit").Infof("Cache merchant rate limits to memory, with total data %d", len(limits))
+	limitCache := make(map[string]entity.MerchantRateLimit, len(limits))
+	for _, limit := range limits {
+		limitCache[rateLimitKey(limit.MerchantCode, limit.BankCode)] = limit
+	}
+
+	s.mu.Lock()
+	s.limitCache = limitCache
+	s.mu.Unlock()
+	return nil
+}
+
+// Check apply the merchant limit and, when destination bankCode is given, the
+// merchant and bank limit, every configured limit must allow the request. The
+// bank limit is keyed on the partner bank routed for the destination, the bank
+// actually receiving the traffic, same as the bulk bank rate
+func (s *rateLimitService) Check(ctx context.Context, merchantCode, bankCode, inquiryType string) (RateLimitDecision, error) {
+	decision := RateLimitDecision{Allowed: true, Remaining: -1}
+
+	merchantLimit, ok := s.getLimit(merchantCode, "")
+	if !ok {
+		merchantLimit = entity.MerchantRateLimit{PerSecond: s.cfg.DefaultPerSecond, PerDay: s.cfg.DefaultPerDay}
+	}
+	if err := s.apply(ctx, &decision, merchantLimit, fmt.Sprintf("ratelimit:%s", merchantCode)); err != nil {
+		return decision, err
+	}
+
+	if bankCode != "" {
+		partnerBankCode := s.routeSvc.PrimaryPartnerBank(merchantCode, bankCode, inquiryType)
+		if bankLimit, ok := s.getLimit(merchantCode, partnerBankCode); ok {
+			if err := s.apply(ctx, &decision, bankLimit, fmt.Sprintf("ratelimit:%s:%s", merchantCode, partnerBankCode)); err != nil {
+				return decision, err
+			}
+		}
+	}
+	return decision, nil
+}
+
+// Wait charge one inquiry made by a background worker against the merchant
+// limits, sleeping while only the per second limit reject it. Like the
+// middleware, redis failure let the inquiry through
+func (s *rateLimitService) Wait(ctx context.Context, merchantCode, bankCode, inquiryType string) error {
+	for {
+		decision, err := s.Check(ctx, merchantCode, bankCode, inquiryType)
+		if err != nil {
+			loghelper.Logger.WithFields(logrus.Fields{
+				"service":  "rate_limit_service",
+				"merchant": merchantCode,
+			}).WithError(err).Warn("Failed to check rate limit, allowing inquiry")
+			return nil
+		}
+		if decision.Allowed {
+			return nil
+		}
+		if decision.RetryAfter > rateLimitMaxWait {
+			return ErrRateLimited
+		}
+		if err := retryhelper.Wait(ctx, decision.RetryAfter); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *rateLimitService) apply(ctx context.Context, decision *RateLimitDecision, limit entity.MerchantRateLimit, keyPrefix string) error {
+	if !decision.Allowed {
+		return nil
+	}
+
+	if limit.PerSecond > 0 {
+		result, err := s.redisRepo.AllowRate(ctx, keyPrefix+":sec", limit.PerSecond)
+		if err != nil {
+			return err
+		}
+		mergeRateLimit(decision, limit.PerSecond, result)
+		if !decision.Allowed {
+			return nil
+		}
+	}
+
+	if limit.PerDay > 0 {
+		now := time.Now().In(snapLocation)
+		endOfDay := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, snapLocation)
+		result, err := s.redisRepo.AllowQuota(ctx, keyPrefix+":day:"+now.Format("20060102"), limit.PerDay, endOfDay.Sub(now))
+		if err != nil {
+			return err
+		}
+		mergeRateLimit(decision, limit.PerDay, result)
+	}
+	return nil
+}
+
+func (s *rateLimitService) getLimit(merchantCode, bankCode string) (entity.MerchantRateLimit, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	limit, ok := s.limitCache[rateLimitKey(merchantCode, bankCode)]
+	return limit, ok
+}
+
+// mergeRateLimit keep the limit with the fewest remaining request, a rejection always win
+func mergeRateLimit(decision *RateLimitDecision, limit int, result repository.RateLimitResult) {
+	if !result.Allowed {
+		decision.Allowed = false
+	} else if decision.Remaining >= 0 && result.Remaining >= decision.Remaining {
+		return
+	}
+
+	decision.Limit = limit
+	decision.Remaining = result.Remaining
+	decision.ResetAfter = result.ResetAfter
+	decision.RetryAfter = result.RetryAfter
+}
+
+func rateLimitKey(merchantCode, bankCode string) string {
+	return merchantCode + "|" + bankCode
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/repository"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const anyDestinationBank = "*"
+
+type RouteService interface {
+	LoadAllRoutes(ctx context.Context) error
+	ResolvePartnerBanks(merchantCode, destinationBankCode, inquiryType string) ([]string, error)
+	PrimaryPartnerBank(merchantCode, destinationBankCode, inquiryType string) string
+}
+
+type routeService struct {
+	mu         sync.RWMutex
+	dbRepo     repository.RouteRepository
+	routeCache map[string][]entity.InquiryRoute
+}
+
+func NewRouteService(dbRepo repository.RouteRepository) RouteService {
+	return &routeService{
+		dbRepo:     dbRepo,
+		routeCache: make(map[string][]entity.InquiryRoute),
+	}
+}
+
+func (s *routeService) LoadAllRoutes(ctx context.Context) error {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "route_service",
+		"operation": "load_inquiry_route",
+	})
+
+	log.WithField("step", "get_data_db").Info("Get inquiry routes from db")
+	routes, err := s.dbRepo.FindAll(ctx)
+	if err != nil {
+		log.WithField("step", "get_data_db").WithError(err).Error("Failed to fetch inquiry routes from database")
+		return err
+	}
+
+	log.WithField("step", "caching_route").Infof("Cache inquiry routes to memory, with total data %d", len(routes))
+	// routes are sorted by priority, appending keep the failover order
+	routeCache := make(map[string][]entity.InquiryRoute)
+	for _, route := range routes {
+		key := routeKey(route.MerchantCode, route.DestinationBankCode, route.InquiryType)
+		routeCache[key] = append(routeCache[key], route)
+	}
+
+	s.mu.Lock()
+	s.routeCache = routeCache
+	s.mu.Unlock()
+	return nil
+}
+
+// ResolvePartnerBanks pick the most specific route, merchant specific route
+// win over the general one, and exact destination bank win over wildcard.
+// The partner banks are returned in failover order.
+func (s *routeService) ResolvePartnerBanks(merchantCode, destinationBankCode, inquiryType string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, merchant := range []string{merchantCode, ""} {
+		for _, destination := range []string{destinationBankCode, anyDestinationBank} {
+			for _, trxType := range []string{inquiryType, ""} {
+				if routes, ok := s.routeCache[routeKey(merchant, destination, trxType)]; ok {
+					partnerBankCodes := make([]string, 0, len(routes))
+					for _, route := range routes {
+						partnerBankCodes = append(partnerBankCodes, route.PartnerBankCode)
+					}
+					return partnerBankCodes, nil
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no inquiry route for destination bank %s with type %s", destinationBankCode, inquiryType)
+}
+
+// PrimaryPartnerBank return the first partner bank routed for the destination,
+// which carry the traffic unless it fail over. Without route the destination
+// bank itself is returned
+func (s *routeService) PrimaryPartnerBank(merchantCode, destinationBankCode, inquiryType string) string {
+	if partnerBankCodes, err := s.ResolvePartnerBanks(merchantCode, destinationBankCode, inquiryType); err == nil && len(partnerBankCodes) > 0 {
+		return partnerBankCodes[0]
+	}
+	return destinationBankCode
+}
+
+func routeKey(merchantCode, destinationBankCode, inquiryType string) string {
+	return fmt.Sprintf("%s|%s|%s", merchantCode, destinationBankCode, inquiryType)
+}
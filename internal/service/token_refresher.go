@@ -0,0 +1,129 @@
+package service
+
+import (
+	"briefcash-inquiry/internal/entity"
+	"briefcash-inquiry/internal/helper/httphelper"
+	"briefcash-inquiry/internal/helper/loghelper"
+	"briefcash-inquiry/internal/helper/routinghelper"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type TokenRefresher interface {
+	Start(ctx context.Context)
+}
+
+type TokenRefresherConfig struct {
+	Interval   time.Duration
+	LeadTime   time.Duration
+	MaxRetry   int
+	RetryDelay time.Duration
+}
+
+type tokenRefresher struct {
+	tokenSvc  TokenService
+	bankRepo  BankPartner
+	providers routinghelper.ProviderRegistry
+	clients   *httphelper.ClientRegistry
+	cfg       TokenRefresherConfig
+}
+
+func NewTokenRefresher(tokenSvc TokenService, bankRepo BankPartner, providers routinghelper.ProviderRegistry, clients *httphelper.ClientRegistry, cfg TokenRefresherConfig) TokenRefresher {
+	return &tokenRefresher{tokenSvc, bankRepo, providers, clients, cfg}
+}
+
+func (r *tokenRefresher) Start(ctx context.Context) {
+	loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "token_refresher",
+		"interval":  r.cfg.Interval.String(),
+		"lead_time": r.cfg.LeadTime.String(),
+	}).Info("Background access token refresher started")
+
+	go func() {
+		ticker := time.NewTicker(r.cfg.Interval)
+		defer ticker.Stop()
+
+		r.refreshAll(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				loghelper.Logger.WithField("service", "token_refresher").Info("Background access token refresher stopped")
+				return
+			case <-ticker.C:
+				r.refreshAll(ctx)
+			}
+		}
+	}()
+}
+
+func (r *tokenRefresher) refreshAll(ctx context.Context) {
+	for _, bank := range r.bankRepo.GetAllBankConfig() {
+		if ctx.Err() != nil {
+			return
+		}
+		r.refreshBank(ctx, bank)
+	}
+}
+
+func (r *tokenRefresher) refreshBank(ctx context.Context, bank entity.BankConfig) {
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"service":   "token_refresher",
+		"operation": "refresh_access_token",
+		"bank_code": bank.BankCode,
+	})
+
+	provider, err := r.providers.Resolve(bank.ProviderKey())
+	if err != nil {
+		log.WithField("step", "resolve_provider").WithError(err).Warn("Skip refreshing token, no provider registered")
+		return
+	}
+	if routinghelper.UsesStaticAuth(provider) {
+		return
+	}
+
+	token, err := r.tokenSvc.GetActiveTokenDetail(ctx, bank.BankCode, bank.ClientKey)
+	if err == nil && time.Until(token.ExpiresDate) > r.leadTime(token) {
+		return
+	}
+
+	log.WithField("step", "refresh_token").Info("Access token missing or about to expire, refreshing token")
+	fetch := bankTokenFetcher(provider, bankHttpClient(r.clients, &bank))
+	delay := r.cfg.RetryDelay
+	for attempt := 1; attempt <= r.cfg.MaxRetry; attempt++ {
+		_, err := r.tokenSvc.RefreshAccessToken(ctx, &bank, fetch, log)
+		if err == nil {
+			log.WithField("step", "refresh_token").Infof("Access token refreshed on attempt %d", attempt)
+			return
+		}
+
+		log.WithFields(logrus.Fields{
+			"step":    "refresh_token",
+			"attempt": attempt,
+		}).WithError(err).Warn("Failed to refresh access token")
+
+		if attempt == r.cfg.MaxRetry {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	log.WithField("step", "refresh_token").Errorf("Giving up refreshing access token after %d attempts", r.cfg.MaxRetry)
+}
+
+// leadTime cap the configured lead time to half of the token lifetime, a bank
+// issuing token shorter than the lead time would otherwise be refreshed every tick
+func (r *tokenRefresher) leadTime(token *entity.AccessToken) time.Duration {
+	lifetime := time.Duration(token.ExpiresIn) * time.Second
+	if lifetime > 0 && r.cfg.LeadTime > lifetime/2 {
+		return lifetime / 2
+	}
+	return r.cfg.LeadTime
+}
@@ -3,9 +3,13 @@ package main
 import (
 	"briefcash-inquiry/config"
 	"briefcash-inquiry/internal/controller"
+	"briefcash-inquiry/internal/helper/breakerhelper"
 	"briefcash-inquiry/internal/helper/dbhelper"
+	"briefcash-inquiry/internal/helper/httphelper"
 	"briefcash-inquiry/internal/helper/loghelper"
 	"briefcash-inquiry/internal/helper/redishelper"
+	"briefcash-inquiry/internal/helper/retryhelper"
+	"briefcash-inquiry/internal/helper/routinghelper"
 	"briefcash-inquiry/internal/repository"
 	"briefcash-inquiry/internal/service"
 	"context"
@@ -62,24 +66,121 @@ func main() {
 
 	inquiryRepo := repository.NewInquiryRepository(dbHelper.DB)
 	partnerRepo := repository.NewPartnerRepository(dbHelper.DB)
+	routeRepo := repository.NewRouteRepository(dbHelper.DB)
 	tokenRepo := repository.NewTokenRepository(dbHelper.DB)
 	tokenRedis := repository.NewTokenRedisRepository(redisClient.Client)
+	idempotencyRepo := repository.NewIdempotencyRepository(dbHelper.DB)
+	idempotencyRedis := repository.NewIdempotencyRedisRepository(redisClient.Client)
+	merchantRepo := repository.NewMerchantRepository(dbHelper.DB)
+	inquiryCacheRepo := repository.NewInquiryCacheRepository(redisClient.Client)
+	bulkJobRepo := repository.NewBulkJobRepository(dbHelper.DB)
+	asyncInquiryRepo := repository.NewAsyncInquiryRepository(dbHelper.DB)
+	merchantTokenRedis := repository.NewMerchantTokenRedisRepository(redisClient.Client)
+	replayRedis := repository.NewReplayRedisRepository(redisClient.Client)
+	rateLimitRepo := repository.NewRateLimitRepository(dbHelper.DB)
+	rateLimitRedis := repository.NewRateLimitRedisRepository(redisClient.Client)
 	partnerService := service.NewPartnerService(partnerRepo)
 
 	if err := partnerService.LoadAllBankPartner(ctx); err != nil {
 		loghelper.Logger.WithError(err).Fatal("Failed to load bank route config to memory")
 	}
 
+	merchantService := service.NewMerchantService(merchantRepo)
+	if err := merchantService.LoadAllMerchant(ctx); err != nil {
+		loghelper.Logger.WithError(err).Fatal("Failed to load merchant settings to memory")
+	}
+
+	routeService := service.NewRouteService(routeRepo)
+	if err := routeService.LoadAllRoutes(ctx); err != nil {
+		loghelper.Logger.WithError(err).Fatal("Failed to load inquiry route to memory")
+	}
+
+	rateLimitService := service.NewRateLimitService(rateLimitRepo, rateLimitRedis, routeService, service.RateLimitConfig{
+		DefaultPerSecond: cfg.RateLimitPerSecond,
+		DefaultPerDay:    cfg.RateLimitPerDay,
+	})
+	if err := rateLimitService.LoadAllLimits(ctx); err != nil {
+		loghelper.Logger.WithError(err).Fatal("Failed to load merchant rate limits to memory")
+	}
+
+	providerRegistry := routinghelper.NewDefaultProviderRegistry()
+	inquiryCacheService := service.NewInquiryCacheService(inquiryCacheRepo, service.InquiryCacheConfig{
+		PositiveTTL: cfg.InquiryCacheTTL,
+		NegativeTTL: cfg.InquiryNegativeTTL,
+	})
+	clientRegistry := httphelper.NewClientRegistry()
+	breakerRegistry := breakerhelper.NewBreakerRegistry(breakerhelper.BreakerConfig{
+		FailureThreshold:    cfg.BreakerFailureLimit,
+		OpenTimeout:         cfg.BreakerOpenTimeout,
+		HalfOpenMaxRequests: cfg.BreakerHalfOpenLimit,
+	})
 	tokenService := service.NewTokenService(dbHelper.DB, tokenRepo, tokenRedis)
-	inquiryService := service.NewInquiryService(inquiryRepo, tokenService, partnerService, dbHelper.DB)
-	inquiryController := controller.NewInquiryController(inquiryService)
+	tokenRefresher := service.NewTokenRefresher(tokenService, partnerService, providerRegistry, clientRegistry, service.TokenRefresherConfig{
+		Interval:   cfg.TokenRefreshInterval,
+		LeadTime:   cfg.TokenRefreshLeadTime,
+		MaxRetry:   cfg.TokenRefreshMaxRetry,
+		RetryDelay: cfg.TokenRefreshRetryDelay,
+	})
+	tokenRefresher.Start(ctx)
+
+	inquiryService := service.NewInquiryService(inquiryRepo, tokenService, partnerService, routeService, providerRegistry, breakerRegistry, clientRegistry, merchantService, inquiryCacheService, dbHelper.DB)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, idempotencyRedis)
+	inquiryController := controller.NewInquiryController(inquiryService, idempotencyService)
+	bulkInquiryService := service.NewBulkInquiryService(bulkJobRepo, inquiryService, routeService, partnerService, rateLimitRedis, rateLimitService, service.BulkInquiryConfig{
+		Workers:       cfg.BulkWorkers,
+		RatePerSecond: cfg.BulkRatePerSecond,
+		MaxRows:       cfg.BulkMaxRows,
+		PollInterval:  cfg.BulkPollInterval,
+		StaleTimeout:  cfg.BulkStaleTimeout,
+	})
+	bulkInquiryService.Start(ctx)
+	bulkInquiryController := controller.NewBulkInquiryController(bulkInquiryService)
+	asyncInquiryService := service.NewAsyncInquiryService(asyncInquiryRepo, inquiryService, idempotencyService, merchantService, rateLimitService, clientRegistry, service.AsyncInquiryConfig{
+		Workers:         cfg.AsyncWorkers,
+		PollInterval:    cfg.AsyncPollInterval,
+		StaleTimeout:    cfg.AsyncStaleTimeout,
+		CallbackTimeout: cfg.CallbackTimeout,
+		CallbackRetry: retryhelper.RetryPolicy{
+			MaxAttempts: cfg.CallbackMaxAttempts,
+			BaseDelay:   cfg.CallbackBaseDelay,
+			MaxDelay:    cfg.CallbackMaxDelay,
+			Jitter:      0.2,
+		},
+	})
+	asyncInquiryService.Start(ctx)
+	asyncInquiryController := controller.NewAsyncInquiryController(asyncInquiryService)
+	inquiryHistoryService := service.NewInquiryHistoryService(inquiryRepo)
+	inquiryHistoryController := controller.NewInquiryHistoryController(inquiryHistoryService)
+	merchantAuthService := service.NewMerchantAuthService(merchantService, merchantTokenRedis, replayRedis, service.MerchantAuthConfig{
+		TokenTTL:        cfg.MerchantTokenTTL,
+		TimestampWindow: cfg.TimestampWindow,
+	})
+	merchantAuthController := controller.NewMerchantAuthController(merchantAuthService)
+	opsController := controller.NewOpsController(breakerRegistry)
 
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(RequestLoggerMiddleware())
 
 	api := router.Group("/api/v1")
-	api.POST("/inquiry", inquiryController.InquiryAccountNumber)
+	api.POST("/access-token/b2b", merchantAuthController.AccessTokenB2B)
+
+	merchantApi := api.Group("")
+	merchantApi.Use(controller.MerchantAuthMiddleware(merchantAuthService))
+	merchantApi.Use(controller.RateLimitMiddleware(rateLimitService))
+	merchantApi.POST("/inquiry", inquiryController.InquiryAccountNumber)
+	merchantApi.GET("/inquiry/:partnerReferenceNo", inquiryController.GetInquiryByReference)
+	merchantApi.POST("/inquiry/async", asyncInquiryController.InquiryAccountAsync)
+	merchantApi.GET("/inquiry/async/:trackingId", asyncInquiryController.GetAsyncTask)
+	merchantApi.POST("/inquiry/bulk", bulkInquiryController.CreateBulkJob)
+	merchantApi.GET("/inquiry/bulk/:jobId", bulkInquiryController.GetBulkJob)
+	merchantApi.GET("/inquiry/bulk/:jobId/results", bulkInquiryController.DownloadBulkResults)
+	merchantApi.GET("/inquiries", inquiryHistoryController.SearchInquiries)
+
+	opsApi := api.Group("/ops")
+	opsApi.Use(controller.OpsAuthMiddleware(cfg.OpsApiKey))
+	opsApi.GET("/inquiries", inquiryHistoryController.SearchAllInquiries)
+	opsApi.GET("/circuit-breakers", opsController.CircuitBreakerStatus)
 
 	server := &http.Server{
 		Addr:    cfg.AppPort,
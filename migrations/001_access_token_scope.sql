@@ -0,0 +1,8 @@
+-- Access token scoped by bank and partner credential. Token saved before this
+-- change has no bank code and is never matched again, a new one is requested.
+
+ALTER TABLE access_token ADD COLUMN IF NOT EXISTS bank_code VARCHAR(10);
+ALTER TABLE access_token ADD COLUMN IF NOT EXISTS client_key VARCHAR(255);
+
+-- latest valid token of a bank credential
+CREATE INDEX IF NOT EXISTS idx_access_token_bank_client ON access_token (bank_code, client_key, expires_date);
@@ -0,0 +1,13 @@
+-- Routing table choosing the partner bank used for an inquiry. Empty merchant
+-- or type and "*" destination match any value.
+
+CREATE TABLE IF NOT EXISTS inquiry_route (
+    id                    BIGSERIAL PRIMARY KEY,
+    merchant_code         VARCHAR(50)  NOT NULL DEFAULT '',
+    destination_bank_code VARCHAR(10)  NOT NULL,
+    inquiry_type          VARCHAR(20)  NOT NULL DEFAULT '',
+    partner_bank_code     VARCHAR(10)  NOT NULL
+);
+
+-- one partner bank per route key
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inquiry_route_key ON inquiry_route (merchant_code, destination_bank_code, inquiry_type);
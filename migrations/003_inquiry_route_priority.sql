@@ -0,0 +1,7 @@
+-- Several partner banks per route key form the failover list, tried by
+-- priority ascending.
+
+ALTER TABLE inquiry_route ADD COLUMN IF NOT EXISTS priority INT NOT NULL DEFAULT 0;
+
+DROP INDEX IF EXISTS idx_inquiry_route_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inquiry_route_priority ON inquiry_route (merchant_code, destination_bank_code, inquiry_type, priority);
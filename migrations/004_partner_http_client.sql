@@ -0,0 +1,4 @@
+-- Per bank tuning of the shared HTTP client, zero keep the default.
+
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS http_timeout_ms INT NOT NULL DEFAULT 0;
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS max_conns_per_host INT NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+-- Per bank retry policy, zero or empty keep the default policy. Status and
+-- response codes are comma separated lists, e.g. 502,503,504.
+
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS retry_max_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS retry_base_delay_ms INT NOT NULL DEFAULT 0;
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS retry_status_codes VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS retry_response_codes VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS idempotent_external_id BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,15 @@
+-- Stored response of completed inquiries, replayed for a repeated partner
+-- reference when redis does not have it anymore.
+
+CREATE TABLE IF NOT EXISTS inquiry_idempotency (
+    id                   BIGSERIAL PRIMARY KEY,
+    merchant_code        VARCHAR(50)  NOT NULL,
+    partner_reference_no VARCHAR(64)  NOT NULL,
+    request_hash         VARCHAR(64)  NOT NULL,
+    http_status          INT          NOT NULL,
+    response_body        TEXT         NOT NULL,
+    created_at           TIMESTAMPTZ  NOT NULL
+);
+
+-- one record per merchant reference, SaveRecord rely on it to keep the first response
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_reference ON inquiry_idempotency (merchant_code, partner_reference_no);
@@ -0,0 +1,9 @@
+-- Per merchant settings of this service, one row per merchant.
+
+CREATE TABLE IF NOT EXISTS merchant_settings (
+    id                    BIGSERIAL PRIMARY KEY,
+    merchant_code         VARCHAR(50)  NOT NULL,
+    disable_inquiry_cache BOOLEAN      NOT NULL DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_merchant_settings_merchant_code ON merchant_settings (merchant_code);
@@ -0,0 +1,11 @@
+-- Partner bank, external id and type of an inquiry, needed to look a stored
+-- inquiry up by reference and re-query its status on the partner bank.
+
+ALTER TABLE inquiry ADD COLUMN IF NOT EXISTS partner_bank_code VARCHAR(10);
+ALTER TABLE inquiry ADD COLUMN IF NOT EXISTS external_id VARCHAR(64);
+ALTER TABLE inquiry ADD COLUMN IF NOT EXISTS inquiry_type VARCHAR(20);
+
+ALTER TABLE partner_url ADD COLUMN IF NOT EXISTS status_inquiry_url VARCHAR(255) NOT NULL DEFAULT '';
+
+-- lookup by partner reference
+CREATE INDEX IF NOT EXISTS idx_inquiry_merchant_reference ON inquiry (merchant_code, partner_reference_no);
@@ -0,0 +1,11 @@
+-- Inquiry lifecycle columns, a row is created PENDING before the bank call and
+-- moved to its final status with the result once the bank answer.
+
+ALTER TABLE inquiry ADD COLUMN IF NOT EXISTS error_code VARCHAR(50);
+ALTER TABLE inquiry ADD COLUMN IF NOT EXISTS bank_response_code VARCHAR(20);
+ALTER TABLE inquiry ADD COLUMN IF NOT EXISTS latency_ms BIGINT NOT NULL DEFAULT 0;
+-- result served from the inquiry cache, partner and bank fields come from the original call
+ALTER TABLE inquiry ADD COLUMN IF NOT EXISTS cached BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE inquiry ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+
+ALTER TABLE inquiry_idempotency ADD COLUMN IF NOT EXISTS bank_response_code VARCHAR(20);
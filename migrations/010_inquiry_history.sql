@@ -0,0 +1,8 @@
+-- Indexes backing inquiry history search and export.
+
+-- merchant history page and export, newest first with keyset on id
+CREATE INDEX IF NOT EXISTS idx_inquiry_merchant_id ON inquiry (merchant_code, id);
+-- search by beneficiary account
+CREATE INDEX IF NOT EXISTS idx_inquiry_account ON inquiry (beneficiary_account);
+-- search by status within a date range
+CREATE INDEX IF NOT EXISTS idx_inquiry_status_date ON inquiry (status, inquiry_date);
@@ -0,0 +1,40 @@
+-- Bulk inquiry job queue, claimed by workers with FOR UPDATE SKIP LOCKED, and
+-- the per partner bank bulk rate, zero keep BULK_INQUIRY_RATE_PER_SECOND.
+
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS bulk_rate_per_second INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS inquiry_bulk_job (
+    id             BIGSERIAL PRIMARY KEY,
+    job_id         VARCHAR(64)  NOT NULL,
+    merchant_code  VARCHAR(50)  NOT NULL,
+    status         VARCHAR(20)  NOT NULL,
+    total_rows     INT          NOT NULL DEFAULT 0,
+    processed_rows INT          NOT NULL DEFAULT 0,
+    success_rows   INT          NOT NULL DEFAULT 0,
+    failed_rows    INT          NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ  NOT NULL,
+    updated_at     TIMESTAMPTZ  NOT NULL,
+    completed_at   TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inquiry_bulk_job_job_id ON inquiry_bulk_job (job_id);
+-- next queued or stale job, oldest first
+CREATE INDEX IF NOT EXISTS idx_bulk_job_status ON inquiry_bulk_job (status, created_at);
+
+CREATE TABLE IF NOT EXISTS inquiry_bulk_item (
+    id                   BIGSERIAL PRIMARY KEY,
+    job_id               VARCHAR(64)  NOT NULL,
+    row_no               INT          NOT NULL,
+    partner_reference_no VARCHAR(64)  NOT NULL,
+    beneficiary_account  VARCHAR(50)  NOT NULL,
+    bank_code            VARCHAR(10)  NOT NULL,
+    inquiry_type         VARCHAR(20),
+    status               VARCHAR(20)  NOT NULL,
+    response_code        VARCHAR(50),
+    response_message     TEXT,
+    beneficiary_name     VARCHAR(255),
+    processed_at         TIMESTAMPTZ
+);
+
+-- pending items and result export of a job, in row order
+CREATE INDEX IF NOT EXISTS idx_bulk_item_job ON inquiry_bulk_item (job_id, row_no);
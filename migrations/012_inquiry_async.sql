@@ -0,0 +1,40 @@
+-- Async inquiry tasks, their merchant callback delivery log and the callback
+-- endpoint registered per merchant.
+
+ALTER TABLE merchant_settings ADD COLUMN IF NOT EXISTS callback_url VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE merchant_settings ADD COLUMN IF NOT EXISTS callback_secret VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS inquiry_async_task (
+    id                   BIGSERIAL PRIMARY KEY,
+    tracking_id          VARCHAR(64)  NOT NULL,
+    merchant_code        VARCHAR(50)  NOT NULL,
+    partner_reference_no VARCHAR(64)  NOT NULL,
+    request_hash         VARCHAR(64)  NOT NULL,
+    request_body         TEXT         NOT NULL,
+    response_body        TEXT,
+    status               VARCHAR(20)  NOT NULL,
+    callback_attempts    INT          NOT NULL DEFAULT 0,
+    next_attempt_at      TIMESTAMPTZ  NOT NULL,
+    created_at           TIMESTAMPTZ  NOT NULL,
+    updated_at           TIMESTAMPTZ  NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inquiry_async_task_tracking_id ON inquiry_async_task (tracking_id);
+-- one task per merchant reference, CreateTask rely on it to skip duplicates
+CREATE UNIQUE INDEX IF NOT EXISTS idx_async_task_reference ON inquiry_async_task (merchant_code, partner_reference_no);
+-- next task due for processing or callback delivery
+CREATE INDEX IF NOT EXISTS idx_async_task_status ON inquiry_async_task (status, next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS inquiry_callback_log (
+    id            BIGSERIAL PRIMARY KEY,
+    tracking_id   VARCHAR(64)  NOT NULL,
+    attempt       INT          NOT NULL,
+    callback_url  TEXT         NOT NULL,
+    http_status   INT          NOT NULL DEFAULT 0,
+    response_body TEXT,
+    error_message TEXT,
+    latency_ms    BIGINT       NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ  NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_inquiry_callback_log_tracking_id ON inquiry_callback_log (tracking_id);
@@ -0,0 +1,6 @@
+-- Merchant credential used for SNAP B2B access token and request signature.
+
+ALTER TABLE merchant_settings ADD COLUMN IF NOT EXISTS client_key VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE merchant_settings ADD COLUMN IF NOT EXISTS client_secret VARCHAR(255) NOT NULL DEFAULT '';
+-- PEM encoded RSA public key
+ALTER TABLE merchant_settings ADD COLUMN IF NOT EXISTS public_key TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,13 @@
+-- Merchant request limits. An empty bank_code row limit every request of the
+-- merchant, a row with bank_code limit traffic sent to that partner bank.
+-- Zero per_second or per_day means no limit.
+
+CREATE TABLE IF NOT EXISTS merchant_rate_limit (
+    id            BIGSERIAL PRIMARY KEY,
+    merchant_code VARCHAR(50)  NOT NULL,
+    bank_code     VARCHAR(10)  NOT NULL DEFAULT '',
+    per_second    INT          NOT NULL DEFAULT 0,
+    per_day       INT          NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_merchant_rate_limit_key ON merchant_rate_limit (merchant_code, bank_code);
@@ -0,0 +1,3 @@
+-- Our account at the partner bank, required by non SNAP interbank inquiry.
+
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS source_account_no VARCHAR(50) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+-- Partner specific additionalInfo sent on SNAP inquiry, a JSON object of
+-- string values or empty when the bank does not require one.
+
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS internal_additional_info TEXT NOT NULL DEFAULT '';
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS external_additional_info TEXT NOT NULL DEFAULT '';
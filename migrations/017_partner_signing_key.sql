@@ -0,0 +1,5 @@
+-- Static signing key and our organization name for bank signing request apart
+-- from the OAuth client secret, e.g. Permata.
+
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS signing_key VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS organization_name VARCHAR(100) NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- Provider serving the partner and its static credential scheme, e.g.
+-- provider_type = 'AGGREGATOR' with auth_scheme = 'basic' or 'api_key'.
+-- Empty provider_type keep the provider registered for the bank code.
+
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS provider_type VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE partner_settings ADD COLUMN IF NOT EXISTS auth_scheme VARCHAR(20) NOT NULL DEFAULT '';